@@ -0,0 +1,127 @@
+package bonsai
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrIncompleteClusterAccess is returned when a ClusterAccess doesn't hold
+// enough information to connect to its cluster.
+var ErrIncompleteClusterAccess = errors.New("incomplete cluster access")
+
+// defaultClusterScheme is the scheme used to connect to a cluster whose
+// ClusterAccess doesn't specify one.
+const defaultClusterScheme = "https"
+
+// BaseURL returns the URL of the cluster's search engine, without
+// credentials; see HTTPClient.
+//
+// The URL is taken from a.URL, when set, as the Host of the ClusterAccess
+// returned on creation isn't fully qualified. Otherwise, it's built from
+// a.Scheme, a.Host and a.Port.
+func (a ClusterAccess) BaseURL() (*url.URL, error) {
+	if a.URL != "" {
+		u, err := url.Parse(a.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			// The error, which may hold credentials, isn't wrapped.
+			return nil, fmt.Errorf("%w: invalid url %q", ErrIncompleteClusterAccess, redactURL(a.URL))
+		}
+		u.User = nil
+		return u, nil
+	}
+
+	if a.Host == "" {
+		return nil, fmt.Errorf("%w: missing host", ErrIncompleteClusterAccess)
+	}
+
+	scheme := a.Scheme
+	if scheme == "" {
+		scheme = defaultClusterScheme
+	}
+
+	host := a.Host
+	if a.Port != 0 {
+		host = net.JoinHostPort(a.Host, strconv.Itoa(a.Port))
+	}
+
+	return &url.URL{Scheme: scheme, Host: host}, nil
+}
+
+// credentials returns the username and password held by a, taken from its
+// URL if its Username and Password aren't set.
+func (a ClusterAccess) credentials() (username, password string) {
+	if a.Username != "" || a.Password != "" || a.URL == "" {
+		return a.Username, a.Password
+	}
+
+	u, err := url.Parse(a.URL)
+	if err != nil || u.User == nil {
+		return "", ""
+	}
+	password, _ = u.User.Password()
+	return u.User.Username(), password
+}
+
+// HTTPClient returns a new http.Client which authenticates requests to the
+// cluster with the credentials held by a, using HTTP basic authentication.
+// Requests to other hosts, including those redirected to, aren't
+// authenticated.
+//
+// As the API only returns a cluster's credentials on creation, those of a
+// Cluster retrieved later must be set before calling HTTPClient; for
+// example, from a CredentialRecord.
+func (a ClusterAccess) HTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &accessTransport{access: a, base: http.DefaultTransport},
+	}
+}
+
+// accessTransport is an http.RoundTripper adding the credentials held by
+// access to requests made to its cluster.
+type accessTransport struct {
+	// access redacts itself, should the transport be formatted.
+	access ClusterAccess
+	base   http.RoundTripper
+}
+
+func (t *accessTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	username, password := t.access.credentials()
+	if username == "" && password == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	baseURL, err := t.access.BaseURL()
+	if err != nil || !sameHost(baseURL, req.URL) {
+		return t.base.RoundTrip(req)
+	}
+
+	// Per the RoundTripper contract, req isn't modified.
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(username, password)
+	return t.base.RoundTrip(req)
+}
+
+// sameHost reports whether a and b address the same host and port.
+func sameHost(a, b *url.URL) bool {
+	return strings.EqualFold(a.Hostname(), b.Hostname()) && urlPort(a) == urlPort(b)
+}
+
+// urlPort returns the port of u, or the default port of its scheme.
+func urlPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch u.Scheme {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,109 @@
+package bonsaitest
+
+import (
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// Fixtures holds the resources a Server is seeded with.
+type Fixtures struct {
+	Clusters []bonsai.Cluster
+	Plans    []bonsai.Plan
+	Spaces   []bonsai.Space
+	Releases []bonsai.Release
+}
+
+// DefaultFixtures returns a small catalog of Plans, Spaces and Releases,
+// modeled after those offered by the Bonsai API, and no Clusters.
+//
+// Each call returns a new copy, which may be modified freely.
+func DefaultFixtures() Fixtures {
+	var (
+		usEast = bonsai.Space{
+			Path:           "omc/bonsai/us-east-1/common",
+			PrivateNetwork: pointer(false),
+			Cloud: &bonsai.CloudProvider{
+				Provider: "aws",
+				Region:   "aws-us-east-1",
+			},
+		}
+		euWest = bonsai.Space{
+			Path:           "omc/bonsai/eu-west-1/common",
+			PrivateNetwork: pointer(false),
+			Cloud: &bonsai.CloudProvider{
+				Provider: "aws",
+				Region:   "aws-eu-west-1",
+			},
+		}
+		gcpUSEast = bonsai.Space{
+			Path:           "omc/bonsai-gcp/us-east4/common",
+			PrivateNetwork: pointer(false),
+			Cloud: &bonsai.CloudProvider{
+				Provider: "gcp",
+				Region:   "gcp-us-east4",
+			},
+		}
+
+		elasticsearch = bonsai.Release{
+			Name:        "Elasticsearch 7.10.2",
+			Slug:        "elasticsearch-7.10.2",
+			ServiceType: "elasticsearch",
+			Version:     "7.10.2",
+			MultiTenant: pointer(true),
+		}
+		opensearch = bonsai.Release{
+			Name:        "OpenSearch 2.6.0",
+			Slug:        "opensearch-2.6.0-mt",
+			ServiceType: "opensearch",
+			Version:     "2.6.0",
+			MultiTenant: pointer(true),
+		}
+		opensearchSingleTenant = bonsai.Release{
+			Name:        "OpenSearch 2.6.0",
+			Slug:        "opensearch-2.6.0",
+			ServiceType: "opensearch",
+			Version:     "2.6.0",
+			MultiTenant: pointer(false),
+		}
+	)
+
+	return Fixtures{
+		Plans: []bonsai.Plan{
+			{
+				Slug:                    "sandbox-aws-us-east-1",
+				Name:                    "Sandbox",
+				PriceInCents:            0,
+				BillingIntervalInMonths: 1,
+				SingleTenant:            pointer(false),
+				PrivateNetwork:          pointer(false),
+				AvailableReleases:       []bonsai.Release{{Slug: elasticsearch.Slug}, {Slug: opensearch.Slug}},
+				AvailableSpaces:         []bonsai.Space{{Path: usEast.Path}},
+			},
+			{
+				Slug:                    "standard-sm",
+				Name:                    "Standard Small",
+				PriceInCents:            5000,
+				BillingIntervalInMonths: 1,
+				SingleTenant:            pointer(false),
+				PrivateNetwork:          pointer(false),
+				AvailableReleases:       []bonsai.Release{{Slug: elasticsearch.Slug}, {Slug: opensearch.Slug}},
+				AvailableSpaces:         []bonsai.Space{{Path: usEast.Path}, {Path: euWest.Path}, {Path: gcpUSEast.Path}},
+			},
+			{
+				Slug:                    "business-sm",
+				Name:                    "Business Small",
+				PriceInCents:            25000,
+				BillingIntervalInMonths: 1,
+				SingleTenant:            pointer(true),
+				PrivateNetwork:          pointer(false),
+				AvailableReleases:       []bonsai.Release{{Slug: elasticsearch.Slug}, {Slug: opensearchSingleTenant.Slug}},
+				AvailableSpaces:         []bonsai.Space{{Path: usEast.Path}, {Path: euWest.Path}},
+			},
+		},
+		Spaces:   []bonsai.Space{usEast, euWest, gcpUSEast},
+		Releases: []bonsai.Release{elasticsearch, opensearch, opensearchSingleTenant},
+	}
+}
+
+func pointer[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,699 @@
+// Package bonsaitest provides an in-process, stateful, fake Bonsai API
+// server, for testing code which depends on a *bonsai.Client without
+// making requests against the real API.
+//
+// The fake implements the Clusters, Plans, Spaces and Releases APIs,
+// including pagination, basic authentication, the API's error response
+// format, and the state transitions that clusters go through when they're
+// created, updated and destroyed.
+package bonsaitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// Default Server configuration.
+const (
+	// DefaultAccessKey is the access key a Server accepts by default.
+	DefaultAccessKey = bonsai.AccessKey("bonsaitest-key")
+	// DefaultAccessToken is the access token a Server accepts by default.
+	DefaultAccessToken = bonsai.AccessToken("bonsaitest-token")
+	// DefaultPageSize is the page size used by list endpoints when the
+	// request doesn't specify one.
+	DefaultPageSize = 100
+	// DefaultTransitionReads is the number of times a cluster in a
+	// transitional state is read before it settles.
+	DefaultTransitionReads = 1
+)
+
+// Cluster state transitions, from each transitional state to the state it
+// settles in.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var settledStates = map[bonsai.ClusterState]bonsai.ClusterState{
+	bonsai.ClusterStateProvisioning:   bonsai.ClusterStateProvisioned,
+	bonsai.ClusterStateUpdatingPlan:   bonsai.ClusterStateProvisioned,
+	bonsai.ClusterStateDeprovisioning: bonsai.ClusterStateDeprovisioned,
+}
+
+var slugNormalizer = regexp.MustCompile("[^a-z0-9]+")
+
+// Request is a record of a request received by a Server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// injectedError is an error response served in place of the next matching
+// request.
+type injectedError struct {
+	method   string
+	path     string
+	status   int
+	messages []string
+}
+
+// cluster is a cluster held by the Server, along with the bookkeeping
+// needed to transition it between states.
+type cluster struct {
+	bonsai.Cluster
+	reads int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithCredentials configures the credentials that the Server requires
+// requests to authenticate with. An empty pair disables authentication.
+func WithCredentials(pair bonsai.CredentialPair) Option {
+	return func(s *Server) {
+		s.credentials = pair
+	}
+}
+
+// WithFixtures seeds the Server with the given resources, replacing
+// [DefaultFixtures].
+func WithFixtures(f Fixtures) Option {
+	return func(s *Server) {
+		s.fixtures = f
+	}
+}
+
+// WithTransitionReads configures the number of times a cluster in a
+// transitional state (for example, PROVISIONING) is read before it settles
+// (for example, into PROVISIONED). Zero settles clusters immediately.
+func WithTransitionReads(n int) Option {
+	return func(s *Server) {
+		s.transitionReads = max(n, 0)
+	}
+}
+
+// Server is a fake Bonsai API server. It's safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	credentials     bonsai.CredentialPair
+	fixtures        Fixtures
+	transitionReads int
+
+	mu       sync.Mutex
+	clusters []*cluster
+	plans    []bonsai.Plan
+	spaces   []bonsai.Space
+	releases []bonsai.Release
+	injected []injectedError
+	requests []Request
+	sequence int
+}
+
+// NewServer starts and returns a new Server. The caller should call Close
+// when finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		credentials: bonsai.CredentialPair{
+			AccessKey:   DefaultAccessKey,
+			AccessToken: DefaultAccessToken,
+		},
+		fixtures:        DefaultFixtures(),
+		transitionReads: DefaultTransitionReads,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.plans = slices.Clone(s.fixtures.Plans)
+	s.spaces = slices.Clone(s.fixtures.Spaces)
+	s.releases = slices.Clone(s.fixtures.Releases)
+	for _, c := range s.fixtures.Clusters {
+		s.clusters = append(s.clusters, &cluster{Cluster: c})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+bonsai.ClusterAPIBasePath, s.listClusters)
+	mux.HandleFunc("POST "+bonsai.ClusterAPIBasePath, s.createCluster)
+	mux.HandleFunc("GET "+bonsai.ClusterAPIBasePath+"/{slug}", s.getCluster)
+	mux.HandleFunc("PUT "+bonsai.ClusterAPIBasePath+"/{slug}", s.updateCluster)
+	mux.HandleFunc("DELETE "+bonsai.ClusterAPIBasePath+"/{slug}", s.destroyCluster)
+	mux.HandleFunc("GET "+bonsai.PlanAPIBasePath, s.listPlans)
+	mux.HandleFunc("GET "+bonsai.PlanAPIBasePath+"/{slug}", s.getPlan)
+	mux.HandleFunc("GET "+bonsai.SpaceAPIBasePath, s.listSpaces)
+	mux.HandleFunc("GET "+bonsai.SpaceAPIBasePath+"/{path...}", s.getSpace)
+	mux.HandleFunc("GET "+bonsai.ReleaseAPIBasePath, s.listReleases)
+	mux.HandleFunc("GET "+bonsai.ReleaseAPIBasePath+"/{slug}", s.getRelease)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No route matches %s %s.", r.Method, r.URL.Path))
+	})
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+
+	return s
+}
+
+// Client returns a *bonsai.Client configured to make requests against the
+// Server, with its credentials, and without client-side rate limits.
+//
+// Additional options are applied after the defaults, and so may override them.
+func (s *Server) Client(opts ...bonsai.ClientOption) *bonsai.Client {
+	defaults := []bonsai.ClientOption{
+		bonsai.WithEndpoint(s.URL),
+		bonsai.WithCredentialPair(s.credentials),
+		bonsai.WithDefaultRateLimit(rate.NewLimiter(rate.Inf, 0)),
+		bonsai.WithProvisionRateLimit(rate.NewLimiter(rate.Inf, 0)),
+		bonsai.WithRetryPolicy(bonsai.RetryPolicy{
+			MaxAttempts: bonsai.DefaultRetryMaxAttempts,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    10 * time.Millisecond,
+		}),
+	}
+	return bonsai.NewClient(append(defaults, opts...)...)
+}
+
+// FailNext configures the Server to respond to the next request matching
+// method and path with an error response of the given status and messages.
+// Rate limited (429) responses instruct clients to retry immediately.
+//
+// Multiple failures may be queued for the same request.
+func (s *Server) FailNext(method, path string, status int, messages ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(messages) == 0 {
+		messages = []string{http.StatusText(status)}
+	}
+	s.injected = append(s.injected, injectedError{
+		method:   method,
+		path:     path,
+		status:   status,
+		messages: messages,
+	})
+}
+
+// Requests returns a record of every request received by the Server.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+// Cluster returns the cluster held by the Server, by its slug, without
+// advancing its state.
+func (s *Server) Cluster(slug string) (bonsai.Cluster, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c := s.cluster(slug); c != nil {
+		return c.Cluster, true
+	}
+	return bonsai.Cluster{}, false
+}
+
+// Clusters returns all clusters held by the Server, including those that
+// have been deprovisioned, without advancing their states.
+func (s *Server) Clusters() []bonsai.Cluster {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clusters := make([]bonsai.Cluster, len(s.clusters))
+	for i, c := range s.clusters {
+		clusters[i] = c.Cluster
+	}
+	return clusters
+}
+
+// SetClusterState forces the cluster with the slug into state.
+func (s *Server) SetClusterState(slug string, state bonsai.ClusterState) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.cluster(slug)
+	if c == nil {
+		return false
+	}
+	c.State, c.reads = state, 0
+	return true
+}
+
+// SetClusterStats replaces the stats of the cluster with the slug.
+func (s *Server) SetClusterStats(slug string, stats bonsai.ClusterStats) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.cluster(slug)
+	if c == nil {
+		return false
+	}
+	c.Stats = stats
+	return true
+}
+
+// middleware records requests, serves injected errors, and authenticates
+// requests, before handing them to next.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Unable to read request body.")
+			return
+		}
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		injected, ok := s.popInjected(r.Method, r.URL.Path)
+		s.mu.Unlock()
+
+		if ok {
+			if injected.status == http.StatusTooManyRequests {
+				w.Header().Set(bonsai.HeaderRetryAfter, "0")
+			}
+			writeError(w, injected.status, injected.messages...)
+			return
+		}
+
+		if s.credentials.NotEmpty() {
+			user, pass, ok := r.BasicAuth()
+			if !ok ||
+				user != string(s.credentials.AccessKey) ||
+				pass != string(s.credentials.AccessToken) {
+				writeError(
+					w,
+					http.StatusUnauthorized,
+					"This request has failed authentication. Please read the docs or email us at support@bonsai.io.",
+				)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) popInjected(method, path string) (injectedError, bool) {
+	for i, injected := range s.injected {
+		if injected.method == method && injected.path == path {
+			s.injected = slices.Delete(s.injected, i, i+1)
+			return injected, true
+		}
+	}
+	return injectedError{}, false
+}
+
+// cluster returns the cluster with the slug, if any. The caller must hold s.mu.
+func (s *Server) cluster(slug string) *cluster {
+	for _, c := range s.clusters {
+		if c.Slug == slug {
+			return c
+		}
+	}
+	return nil
+}
+
+// read returns the cluster as observed by a client, advancing it to its
+// settled state once it's been observed enough times in a transitional
+// state. The caller must hold s.mu.
+func (s *Server) read(c *cluster) bonsai.Cluster {
+	if settled, ok := settledStates[c.State]; ok {
+		if c.reads >= s.transitionReads {
+			c.State, c.reads = settled, 0
+		} else {
+			c.reads++
+		}
+	}
+	return c.Cluster
+}
+
+func (s *Server) plan(slug string) (bonsai.Plan, bool) {
+	i := slices.IndexFunc(s.plans, func(p bonsai.Plan) bool { return p.Slug == slug })
+	if i < 0 {
+		return bonsai.Plan{}, false
+	}
+	return s.plans[i], true
+}
+
+func (s *Server) listClusters(w http.ResponseWriter, r *http.Request) {
+	var (
+		query    = r.URL.Query()
+		name     = strings.ToLower(query.Get("q"))
+		location = query.Get("location")
+	)
+
+	switch tenancy := query.Get("tenancy"); tenancy {
+	case "", bonsai.ClusterTenancyParent, bonsai.ClusterTenancyChild:
+	default:
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Invalid tenancy: %s.", tenancy))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matching := make([]*cluster, 0, len(s.clusters))
+	for _, c := range s.clusters {
+		switch {
+		case c.State == bonsai.ClusterStateDeprovisioned:
+		case name != "" && !strings.Contains(strings.ToLower(c.Name), name):
+		case location != "" && !strings.HasPrefix(c.Space.Path, location):
+		default:
+			matching = append(matching, c)
+		}
+	}
+
+	page, pagination, ok := paginate(w, r, matching)
+	if !ok {
+		return
+	}
+
+	result := wireClusterList{Pagination: pagination, Clusters: make([]wireCluster, len(page))}
+	for i, c := range page {
+		result.Clusters[i] = newWireCluster(s.read(c))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getCluster(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.cluster(slug)
+	if c == nil || c.State == bonsai.ClusterStateDeprovisioned {
+		writeClusterNotFound(w, slug)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, wireClusterGet{Cluster: newWireCluster(s.read(c))})
+}
+
+func (s *Server) createCluster(w http.ResponseWriter, r *http.Request) {
+	opt := bonsai.ClusterCreateOpts{}
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		writeError(w, http.StatusBadRequest, "Request body must be valid JSON.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, space, release, errs := s.resolveCreate(opt)
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	s.sequence++
+	slug := fmt.Sprintf("%.20s-%010d", strings.Trim(slugNormalizer.ReplaceAllString(strings.ToLower(opt.Name), "-"), "-"), s.sequence)
+	host := slug + ".bonsaisearch.net"
+	access := bonsai.ClusterAccess{
+		Host:     host,
+		Port:     443,
+		Scheme:   "https",
+		Username: fmt.Sprintf("user%06d", s.sequence),
+		Password: fmt.Sprintf("pass%06d", s.sequence),
+	}
+	access.URL = fmt.Sprintf("%s://%s:%s@%s:%d", access.Scheme, access.Username, access.Password, host, access.Port)
+
+	uri := s.URL + bonsai.ClusterAPIBasePath + "/" + slug
+	s.clusters = append(s.clusters, &cluster{Cluster: bonsai.Cluster{
+		Slug:    slug,
+		Name:    opt.Name,
+		URI:     uri,
+		Plan:    bonsai.Plan{Slug: plan.Slug, URI: s.URL + bonsai.PlanAPIBasePath + "/" + plan.Slug},
+		Release: release,
+		Space:   space,
+		Access:  bonsai.ClusterAccess{Host: host, Port: access.Port, Scheme: access.Scheme},
+		State:   bonsai.ClusterStateProvisioning,
+	}})
+
+	writeJSON(w, http.StatusAccepted, wireClusterCreate{
+		Message: "Your cluster is being provisioned.",
+		Monitor: uri,
+		Access:  newWireAccess(access),
+		Status:  http.StatusAccepted,
+	})
+}
+
+// resolveCreate resolves the Plan, Space and Release requested by opt,
+// falling back to the first available for the Plan. The caller must hold s.mu.
+func (s *Server) resolveCreate(opt bonsai.ClusterCreateOpts) (bonsai.Plan, bonsai.Space, bonsai.Release, []string) {
+	var (
+		plan    bonsai.Plan
+		space   bonsai.Space
+		release bonsai.Release
+		errs    []string
+		ok      bool
+	)
+
+	if opt.Name == "" {
+		errs = append(errs, "Name can't be blank.")
+	}
+
+	switch {
+	case opt.Plan == "" && len(s.plans) > 0:
+		plan = s.plans[0]
+	case opt.Plan == "":
+		return plan, space, release, append(errs, "No plans are available.")
+	default:
+		if plan, ok = s.plan(opt.Plan); !ok {
+			return plan, space, release, append(errs, fmt.Sprintf("Plan %s not found.", opt.Plan))
+		}
+	}
+
+	spacePath := opt.Space
+	if spacePath == "" && len(plan.AvailableSpaces) > 0 {
+		spacePath = plan.AvailableSpaces[0].Path
+	}
+	if !slices.ContainsFunc(plan.AvailableSpaces, func(sp bonsai.Space) bool { return sp.Path == spacePath }) {
+		errs = append(errs, fmt.Sprintf("Space %s is not available for plan %s.", spacePath, plan.Slug))
+	} else if i := slices.IndexFunc(s.spaces, func(sp bonsai.Space) bool { return sp.Path == spacePath }); i >= 0 {
+		space = s.spaces[i]
+	} else {
+		space = bonsai.Space{Path: spacePath}
+	}
+
+	releaseSlug := opt.Release
+	if releaseSlug == "" && len(plan.AvailableReleases) > 0 {
+		releaseSlug = plan.AvailableReleases[0].Slug
+	}
+	if !slices.ContainsFunc(plan.AvailableReleases, func(rl bonsai.Release) bool { return rl.Slug == releaseSlug }) {
+		errs = append(errs, fmt.Sprintf("Release %s is not available for plan %s.", releaseSlug, plan.Slug))
+	} else if i := slices.IndexFunc(s.releases, func(rl bonsai.Release) bool { return rl.Slug == releaseSlug }); i >= 0 {
+		release = s.releases[i]
+	} else {
+		release = bonsai.Release{Slug: releaseSlug}
+	}
+
+	return plan, space, release, errs
+}
+
+func (s *Server) updateCluster(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+
+	opt := bonsai.ClusterUpdateOpts{}
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		writeError(w, http.StatusBadRequest, "Request body must be valid JSON.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.cluster(slug)
+	if c == nil || c.State == bonsai.ClusterStateDeprovisioned {
+		writeClusterNotFound(w, slug)
+		return
+	}
+
+	if opt.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Name can't be blank.")
+		return
+	}
+	c.Name = opt.Name
+
+	if opt.Plan != "" && opt.Plan != c.Plan.Slug {
+		plan, ok := s.plan(opt.Plan)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Plan %s not found.", opt.Plan))
+			return
+		}
+		c.Plan = bonsai.Plan{Slug: plan.Slug, URI: s.URL + bonsai.PlanAPIBasePath + "/" + plan.Slug}
+		c.State, c.reads = bonsai.ClusterStateUpdatingPlan, 0
+	}
+
+	writeJSON(w, http.StatusAccepted, wireClusterChange{
+		Message: "Your cluster is being updated.",
+		Monitor: c.URI,
+		Status:  http.StatusAccepted,
+	})
+}
+
+func (s *Server) destroyCluster(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.cluster(slug)
+	if c == nil || c.State == bonsai.ClusterStateDeprovisioned {
+		writeClusterNotFound(w, slug)
+		return
+	}
+	c.State, c.reads = bonsai.ClusterStateDeprovisioning, 0
+
+	writeJSON(w, http.StatusAccepted, wireClusterChange{
+		Message: "Your cluster is being deprovisioned.",
+		Monitor: c.URI,
+		Status:  http.StatusAccepted,
+	})
+}
+
+func (s *Server) listPlans(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page, pagination, ok := paginate(w, r, s.plans)
+	if !ok {
+		return
+	}
+
+	result := wirePlanList{Pagination: pagination, Plans: make([]wirePlan, len(page))}
+	for i, p := range page {
+		result.Plans[i] = newWirePlan(p)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getPlan(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, ok := s.plan(slug)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Plan %s not found.", slug))
+		return
+	}
+	writeJSON(w, http.StatusOK, newWirePlan(plan))
+}
+
+func (s *Server) listSpaces(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page, pagination, ok := paginate(w, r, s.spaces)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, wireSpaceList{Pagination: pagination, Spaces: page})
+}
+
+func (s *Server) getSpace(w http.ResponseWriter, r *http.Request) {
+	spacePath := r.PathValue("path")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.spaces, func(sp bonsai.Space) bool { return sp.Path == spacePath })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Space %s not found.", spacePath))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.spaces[i])
+}
+
+func (s *Server) listReleases(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page, pagination, ok := paginate(w, r, s.releases)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, wireReleaseList{Pagination: pagination, Releases: page})
+}
+
+func (s *Server) getRelease(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.releases, func(rl bonsai.Release) bool { return rl.Slug == slug })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Release %s not found.", slug))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.releases[i])
+}
+
+// paginate returns the page of items requested by r's page and size query
+// parameters, or writes an error response and returns false if they're invalid.
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) ([]T, wirePagination, bool) {
+	pagination := wirePagination{PageNumber: 1, PageSize: DefaultPageSize, TotalRecords: len(items)}
+
+	for param, dest := range map[string]*int{"page": &pagination.PageNumber, "size": &pagination.PageSize} {
+		v := r.URL.Query().Get(param)
+		if v == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || (param == "size" && n > DefaultPageSize) {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Invalid %s parameter: %s.", param, v))
+			return nil, pagination, false
+		}
+		*dest = n
+	}
+
+	start := min((pagination.PageNumber-1)*pagination.PageSize, len(items))
+	end := min(start+pagination.PageSize, len(items))
+
+	return items[start:end], pagination, true
+}
+
+func writeClusterNotFound(w http.ResponseWriter, slug string) {
+	writeError(
+		w,
+		http.StatusNotFound,
+		fmt.Sprintf("Cluster %s not found.", slug),
+		"Please review the documentation available at https://docs.bonsai.io",
+		"Undefined request.",
+	)
+}
+
+func writeError(w http.ResponseWriter, status int, messages ...string) {
+	writeJSON(w, status, wireError{Errors: messages, Status: status})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,210 @@
+package bonsaitest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/bonsaitest"
+)
+
+type ServerTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all HTTP request tests
+	suite.Suite
+
+	// server is the fake API server, recreated for each test
+	server *bonsaitest.Server
+	// client is wired to make requests against server
+	client *bonsai.Client
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	s.server = bonsaitest.NewServer(
+		bonsaitest.WithFixtures(bonsaitest.Fixtures{
+			Clusters: []bonsai.Cluster{
+				{
+					Slug:  "existing-cluster-1234567890",
+					Name:  "existing_cluster",
+					Plan:  bonsai.Plan{Slug: "standard-sm"},
+					Space: bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+					State: bonsai.ClusterStateProvisioned,
+				},
+				{
+					Slug:  "other-cluster-1234567890",
+					Name:  "other_cluster",
+					Plan:  bonsai.Plan{Slug: "standard-sm"},
+					Space: bonsai.Space{Path: "omc/bonsai/eu-west-1/common"},
+					State: bonsai.ClusterStateProvisioned,
+				},
+			},
+			Plans:    bonsaitest.DefaultFixtures().Plans,
+			Spaces:   bonsaitest.DefaultFixtures().Spaces,
+			Releases: bonsaitest.DefaultFixtures().Releases,
+		}),
+	)
+	s.client = s.server.Client()
+
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func (s *ServerTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) TestCatalog() {
+	ctx := context.Background()
+	fixtures := bonsaitest.DefaultFixtures()
+
+	plans, err := s.client.Plan.All(ctx)
+	s.NoError(err, "list plans")
+	s.Len(plans, len(fixtures.Plans))
+
+	plan, err := s.client.Plan.GetBySlug(ctx, "standard-sm")
+	s.NoError(err, "get plan")
+	s.Equal(fixtures.Plans[1], plan)
+
+	spaces, err := s.client.Space.All(ctx)
+	s.NoError(err, "list spaces")
+	s.Equal(fixtures.Spaces, spaces)
+
+	space, err := s.client.Space.GetByPath(ctx, "omc/bonsai/eu-west-1/common")
+	s.NoError(err, "get space by multi-segment path")
+	s.Equal(fixtures.Spaces[1], space)
+
+	releases, err := s.client.Release.All(ctx)
+	s.NoError(err, "list releases")
+	s.Equal(fixtures.Releases, releases)
+
+	_, err = s.client.Release.GetBySlug(ctx, "solr-9")
+	s.ErrorIs(err, bonsai.ErrHTTPStatusNotFound)
+}
+
+func (s *ServerTestSuite) TestClusterLifecycle() {
+	ctx := context.Background()
+
+	created, err := s.client.Cluster.Create(ctx, bonsai.ClusterCreateOpts{
+		Name:    "new cluster",
+		Plan:    "sandbox-aws-us-east-1",
+		Space:   "omc/bonsai/us-east-1/common",
+		Release: "opensearch-2.6.0-mt",
+	})
+	s.NoError(err, "create cluster")
+	s.NotEmpty(created.Access.Username, "credentials are shown on creation")
+	s.NotEmpty(created.Access.Password, "credentials are shown on creation")
+
+	clusters := s.server.Clusters()
+	s.Len(clusters, 3)
+	slug := clusters[2].Slug
+	s.Equal(bonsai.ClusterStateProvisioning, clusters[2].State)
+
+	cluster, err := s.client.Cluster.GetBySlug(ctx, slug)
+	s.NoError(err, "get new cluster")
+	s.Equal(bonsai.ClusterStateProvisioning, cluster.State, "transitional state is observed")
+	s.Empty(cluster.Access.Password, "credentials are only shown on creation")
+	s.Equal("opensearch", cluster.Release.ServiceType)
+
+	cluster, err = s.client.Cluster.WaitForState(ctx, slug, bonsai.ClusterWaitOpts{
+		Targets:      []bonsai.ClusterState{bonsai.ClusterStateProvisioned},
+		PollInterval: 1,
+	})
+	s.NoError(err, "cluster settles")
+	s.Equal(bonsai.ClusterStateProvisioned, cluster.State)
+
+	_, err = s.client.Cluster.Update(ctx, slug, bonsai.ClusterUpdateOpts{Name: "new cluster", Plan: "standard-sm"})
+	s.NoError(err, "update cluster")
+
+	cluster, err = s.client.Cluster.GetBySlug(ctx, slug)
+	s.NoError(err, "get updated cluster")
+	s.Equal(bonsai.ClusterStateUpdatingPlan, cluster.State)
+	s.Equal("standard-sm", cluster.Plan.Slug)
+
+	_, err = s.client.Cluster.Destroy(ctx, slug)
+	s.NoError(err, "destroy cluster")
+
+	cluster, err = s.client.Cluster.WaitForState(ctx, slug, bonsai.ClusterWaitOpts{
+		Targets:      []bonsai.ClusterState{bonsai.ClusterStateDeprovisioned},
+		PollInterval: 1,
+	})
+	s.NoError(err, "cluster is deprovisioned")
+	s.Equal(bonsai.ClusterStateDeprovisioned, cluster.State)
+
+	all, err := s.client.Cluster.All(ctx)
+	s.NoError(err, "list clusters")
+	s.Len(all, 2, "deprovisioned clusters aren't listed")
+}
+
+func (s *ServerTestSuite) TestCreateValidation() {
+	_, err := s.client.Cluster.Create(context.Background(), bonsai.ClusterCreateOpts{
+		Name:  "misplaced",
+		Plan:  "sandbox-aws-us-east-1",
+		Space: "omc/bonsai/eu-west-1/common",
+	})
+	s.ErrorIs(err, bonsai.ErrHTTPStatusUnprocessableEntity)
+
+	respErr := bonsai.ResponseError{}
+	s.ErrorAs(err, &respErr)
+	s.Equal([]string{"Space omc/bonsai/eu-west-1/common is not available for plan sandbox-aws-us-east-1."}, respErr.Errors)
+}
+
+func (s *ServerTestSuite) TestListFilteringAndPagination() {
+	ctx := context.Background()
+
+	clusters, pagination, err := s.client.Cluster.List(
+		ctx,
+		bonsai.ClusterAllOpts{Location: "omc/bonsai/eu-west-1"},
+		bonsai.PageOpts{},
+	)
+	s.NoError(err, "list clusters by location")
+	s.Len(clusters, 1)
+	s.Equal("other-cluster-1234567890", clusters[0].Slug)
+	s.Equal(1, pagination.TotalRecords)
+
+	clusters, err = s.client.Cluster.Iter(bonsai.ClusterAllOpts{Query: "CLUSTER"}, bonsai.PageOpts{Size: 1}).All(ctx)
+	s.NoError(err, "list clusters by name, one page at a time")
+	s.Len(clusters, 2)
+
+	requests := s.server.Requests()
+	s.Len(requests, 3)
+	s.Equal("2", requests[2].Query.Get("page"))
+}
+
+func (s *ServerTestSuite) TestErrors() {
+	ctx := context.Background()
+
+	s.Run("unauthorized", func() {
+		client := s.server.Client(bonsai.WithCredentialPair(bonsai.CredentialPair{
+			AccessKey:   "wrong",
+			AccessToken: "credentials",
+		}))
+		_, err := client.Cluster.All(ctx)
+		s.ErrorIs(err, bonsai.ErrHTTPStatusUnauthorized)
+	})
+
+	s.Run("not found", func() {
+		_, err := s.client.Cluster.GetBySlug(ctx, "missing-1234567890")
+		s.ErrorIs(err, bonsai.ErrHTTPStatusNotFound)
+	})
+
+	s.Run("injected failures", func() {
+		s.server.FailNext(http.MethodGet, "/clusters/existing-cluster-1234567890", http.StatusTooManyRequests)
+
+		cluster, err := s.client.Cluster.GetBySlug(ctx, "existing-cluster-1234567890")
+		s.NoError(err, "rate limited request is retried")
+		s.Equal("existing_cluster", cluster.Name)
+
+		s.server.FailNext(http.MethodDelete, "/clusters/existing-cluster-1234567890", http.StatusForbidden, "Nope.")
+		_, err = s.client.Cluster.Destroy(ctx, "existing-cluster-1234567890")
+		s.ErrorIs(err, bonsai.ErrHTTPStatusForbidden)
+	})
+}
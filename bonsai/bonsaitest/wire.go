@@ -0,0 +1,145 @@
+package bonsaitest
+
+import (
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// The types in this file mirror the JSON representations sent by the
+// Bonsai API, which don't always match the way the bonsai package's types
+// marshal themselves.
+
+type wirePlan struct {
+	Slug                    string   `json:"slug"`
+	Name                    string   `json:"name,omitempty"`
+	PriceInCents            int64    `json:"price_in_cents"`
+	BillingIntervalInMonths int      `json:"billing_interval_in_months,omitempty"`
+	SingleTenant            *bool    `json:"single_tenant,omitempty"`
+	PrivateNetwork          *bool    `json:"private_network,omitempty"`
+	AvailableReleases       []string `json:"available_releases"`
+	AvailableSpaces         []string `json:"available_spaces"`
+	URI                     string   `json:"uri,omitempty"`
+}
+
+func newWirePlan(p bonsai.Plan) wirePlan {
+	w := wirePlan{
+		Slug:                    p.Slug,
+		Name:                    p.Name,
+		PriceInCents:            p.PriceInCents,
+		BillingIntervalInMonths: p.BillingIntervalInMonths,
+		SingleTenant:            p.SingleTenant,
+		PrivateNetwork:          p.PrivateNetwork,
+		AvailableReleases:       make([]string, len(p.AvailableReleases)),
+		AvailableSpaces:         make([]string, len(p.AvailableSpaces)),
+		URI:                     p.URI,
+	}
+	for i, release := range p.AvailableReleases {
+		w.AvailableReleases[i] = release.Slug
+	}
+	for i, space := range p.AvailableSpaces {
+		w.AvailableSpaces[i] = space.Path
+	}
+	return w
+}
+
+type wirePlanRef struct {
+	Slug string `json:"slug"`
+	URI  string `json:"uri,omitempty"`
+}
+
+type wireAccess struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Scheme   string `json:"scheme"`
+	Username string `json:"user,omitempty"`
+	Password string `json:"pass,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+func newWireAccess(a bonsai.ClusterAccess) wireAccess {
+	return wireAccess{
+		Host:     a.Host,
+		Port:     a.Port,
+		Scheme:   a.Scheme,
+		Username: a.Username,
+		Password: a.Password,
+		URL:      a.URL,
+	}
+}
+
+type wireCluster struct {
+	Slug    string              `json:"slug"`
+	Name    string              `json:"name"`
+	URI     string              `json:"uri"`
+	Plan    wirePlanRef         `json:"plan"`
+	Release bonsai.Release      `json:"release"`
+	Space   bonsai.Space        `json:"space"`
+	Stats   bonsai.ClusterStats `json:"stats"`
+	Access  wireAccess          `json:"access"`
+	State   bonsai.ClusterState `json:"state"`
+}
+
+func newWireCluster(c bonsai.Cluster) wireCluster {
+	// Credentials are only ever shown once, during cluster creation.
+	access := c.Access
+	access.Username, access.Password, access.URL = "", "", ""
+
+	return wireCluster{
+		Slug:    c.Slug,
+		Name:    c.Name,
+		URI:     c.URI,
+		Plan:    wirePlanRef{Slug: c.Plan.Slug, URI: c.Plan.URI},
+		Release: c.Release,
+		Space:   c.Space,
+		Stats:   c.Stats,
+		Access:  newWireAccess(access),
+		State:   c.State,
+	}
+}
+
+type wirePagination struct {
+	PageNumber   int `json:"page_number"`
+	PageSize     int `json:"page_size"`
+	TotalRecords int `json:"total_records"`
+}
+
+type wireClusterList struct {
+	Pagination wirePagination `json:"pagination"`
+	Clusters   []wireCluster  `json:"clusters"`
+}
+
+type wirePlanList struct {
+	Pagination wirePagination `json:"pagination"`
+	Plans      []wirePlan     `json:"plans"`
+}
+
+type wireSpaceList struct {
+	Pagination wirePagination `json:"pagination"`
+	Spaces     []bonsai.Space `json:"spaces"`
+}
+
+type wireReleaseList struct {
+	Pagination wirePagination   `json:"pagination"`
+	Releases   []bonsai.Release `json:"releases"`
+}
+
+type wireClusterGet struct {
+	Cluster wireCluster `json:"cluster"`
+}
+
+type wireClusterCreate struct {
+	Message string     `json:"message"`
+	Monitor string     `json:"monitor"`
+	Access  wireAccess `json:"access"`
+	Status  int        `json:"status"`
+}
+
+type wireClusterChange struct {
+	Message string `json:"message"`
+	Monitor string `json:"monitor"`
+	Status  int    `json:"status"`
+}
+
+type wireError struct {
+	Errors []string `json:"errors"`
+	Status int      `json:"status"`
+}
@@ -0,0 +1,314 @@
+package bonsai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultBulkConcurrency is the default number of clusters operated on at
+// once by [ClusterClient.Bulk].
+const DefaultBulkConcurrency = 4
+
+// ErrBulkSkipped is matched by the errors of clusters which
+// [ClusterClient.Bulk] didn't operate on, having stopped after an earlier
+// failure, or because its context was done.
+var ErrBulkSkipped = errors.New("skipped")
+
+// BulkAction identifies the operation applied to each cluster by
+// [ClusterClient.Bulk].
+type BulkAction string
+
+const (
+	// BulkActionUpdate updates each cluster, per BulkClustersOpts.Update.
+	BulkActionUpdate BulkAction = "update"
+	// BulkActionDestroy destroys each cluster.
+	BulkActionDestroy BulkAction = "destroy"
+)
+
+// BulkMode determines how [ClusterClient.Bulk] responds to failures.
+type BulkMode int
+
+const (
+	// BulkBestEffort operates on every cluster, regardless of failures.
+	BulkBestEffort BulkMode = iota
+	// BulkStopOnError stops operating on clusters after the first failure.
+	// Operations already underway are completed; the remaining clusters
+	// are skipped.
+	BulkStopOnError
+)
+
+// BulkClustersOpts configures [ClusterClient.Bulk].
+type BulkClustersOpts struct {
+	// Required. The operation applied to each cluster.
+	Action BulkAction
+	// Optional. The update applied to each cluster by BulkActionUpdate.
+	// If Update.Name is empty, each cluster keeps its current name, which
+	// is retrieved with an additional request.
+	Update ClusterUpdateOpts
+	// Optional. The maximum number of clusters operated on at once.
+	// Default: DefaultBulkConcurrency.
+	Concurrency int
+	// Optional. How failures are handled. Default: BulkBestEffort.
+	Mode BulkMode
+	// Optional. Receives a BulkClustersProgress as each cluster is operated
+	// on, or skipped, and is closed once Bulk returns. Sends block, so the
+	// channel must be received from by another goroutine.
+	Progress chan<- BulkClustersProgress
+}
+
+func (o BulkClustersOpts) Valid() error {
+	switch o.Action {
+	case BulkActionUpdate, BulkActionDestroy:
+	case "":
+		return errors.New("action can't be empty")
+	default:
+		return fmt.Errorf("unknown action %q", o.Action)
+	}
+	if o.Concurrency < 0 {
+		return errors.New("concurrency can't be negative")
+	}
+	if o.Mode != BulkBestEffort && o.Mode != BulkStopOnError {
+		return fmt.Errorf("unknown mode %d", o.Mode)
+	}
+	return nil
+}
+
+// withDefaults fills in any unset optional values.
+func (o BulkClustersOpts) withDefaults() BulkClustersOpts {
+	if o.Concurrency == 0 {
+		o.Concurrency = DefaultBulkConcurrency
+	}
+	return o
+}
+
+// BulkClusterResult is the outcome of operating on a single cluster with
+// [ClusterClient.Bulk].
+type BulkClusterResult struct {
+	// Slug of the cluster operated on.
+	Slug string
+	// Message contains details about the request, from its response.
+	Message string
+	// Monitor holds a URI to the Cluster overview page, from the response.
+	Monitor string
+	// Err holds the error operating on the cluster, if any. It matches
+	// ErrBulkSkipped if the cluster wasn't operated on.
+	Err error
+}
+
+// Skipped reports whether the cluster wasn't operated on.
+func (r BulkClusterResult) Skipped() bool {
+	return errors.Is(r.Err, ErrBulkSkipped)
+}
+
+// BulkClustersProgress reports the progress of [ClusterClient.Bulk], as each
+// cluster is operated on, or skipped.
+type BulkClustersProgress struct {
+	// Result is the outcome for the cluster just operated on, or skipped.
+	Result BulkClusterResult
+	// Done is the number of clusters operated on, or skipped, so far,
+	// including that of Result.
+	Done int
+	// Failed is the number of clusters whose operation failed so far.
+	Failed int
+	// Skipped is the number of clusters skipped so far.
+	Skipped int
+	// Total is the number of clusters requested.
+	Total int
+}
+
+// Bulk applies opt.Action to each of the clusters associated with the
+// slugs, operating on up to opt.Concurrency clusters at once. Each request
+// waits on the Client's rate limiters, which are shared by all of the
+// clusters, and any other requests being made.
+//
+// A result is returned for every slug, in the same order. The error joins
+// those of the clusters which failed, along with that of the context, if
+// it was done before every cluster was operated on.
+func (c *ClusterClient) Bulk(ctx context.Context, slugs []string, opt BulkClustersOpts) (
+	[]BulkClusterResult,
+	error,
+) {
+	if opt.Progress != nil {
+		defer close(opt.Progress)
+	}
+
+	if err := opt.Valid(); err != nil {
+		return nil, fmt.Errorf("invalid bulk options (%+v): %w", opt, err)
+	}
+	if err := validBulkSlugs(slugs); err != nil {
+		return nil, fmt.Errorf("invalid bulk slugs: %w", err)
+	}
+	opt = opt.withDefaults()
+
+	b := bulkRun{
+		client:  c,
+		opt:     opt,
+		slugs:   slugs,
+		results: make([]BulkClusterResult, len(slugs)),
+	}
+	return b.run(ctx)
+}
+
+// validBulkSlugs returns an error if any slug is empty or repeated, such
+// that no cluster is operated on twice.
+func validBulkSlugs(slugs []string) error {
+	seen := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		if slug == "" {
+			return errors.New("slug can't be empty")
+		}
+		if seen[slug] {
+			return fmt.Errorf("duplicate slug %q", slug)
+		}
+		seen[slug] = true
+	}
+	return nil
+}
+
+// bulkRun holds the state of a single call to Bulk.
+type bulkRun struct {
+	client  *ClusterClient
+	opt     BulkClustersOpts
+	slugs   []string
+	results []BulkClusterResult
+}
+
+// bulkOutcome is the result for the cluster at index i of the slugs.
+type bulkOutcome struct {
+	i      int
+	result BulkClusterResult
+}
+
+func (b *bulkRun) run(ctx context.Context) ([]BulkClusterResult, error) {
+	var (
+		jobs     = make(chan int)
+		outcomes = make(chan bulkOutcome)
+		// stop is closed to stop dispatching clusters, after a failure in
+		// BulkStopOnError mode.
+		stop     = make(chan struct{})
+		stopOnce sync.Once
+		workers  sync.WaitGroup
+	)
+
+	for range min(b.opt.Concurrency, len(b.slugs)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range jobs {
+				result := b.apply(ctx, b.slugs[i])
+				// Dispatching stops before the failure is reported, such
+				// that no further clusters are dispatched in the meantime.
+				if result.Err != nil && b.opt.Mode == BulkStopOnError {
+					stopOnce.Do(func() { close(stop) })
+				}
+				outcomes <- bulkOutcome{i: i, result: result}
+			}
+		}()
+	}
+
+	// Dispatch clusters to the workers until they're all dispatched, or
+	// dispatching stops; the remainder are skipped.
+	go func() {
+		defer func() {
+			close(jobs)
+			workers.Wait()
+			close(outcomes)
+		}()
+
+		for i := range b.slugs {
+			if !dispatch(ctx, jobs, stop, i) {
+				for j := i; j < len(b.slugs); j++ {
+					outcomes <- bulkOutcome{i: j, result: b.skipped(ctx, b.slugs[j])}
+				}
+				return
+			}
+		}
+	}()
+
+	var (
+		progress = BulkClustersProgress{Total: len(b.slugs)}
+		errs     []error
+	)
+	for outcome := range outcomes {
+		b.results[outcome.i] = outcome.result
+
+		progress.Result = outcome.result
+		progress.Done++
+		switch err := outcome.result.Err; {
+		case err == nil:
+		case errors.Is(err, ErrBulkSkipped):
+			progress.Skipped++
+		default:
+			progress.Failed++
+			errs = append(errs, fmt.Errorf("cluster %s: %w", outcome.result.Slug, err))
+		}
+
+		if b.opt.Progress != nil {
+			b.opt.Progress <- progress
+		}
+	}
+
+	if progress.Skipped > 0 && ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	return b.results, errors.Join(errs...)
+}
+
+// dispatch sends i to the workers, unless dispatching has stopped or ctx is
+// done first, reporting whether it was sent.
+func dispatch(ctx context.Context, jobs chan<- int, stop <-chan struct{}, i int) bool {
+	// A stop takes precedence over workers ready to receive.
+	select {
+	case <-stop:
+		return false
+	case <-ctx.Done():
+		return false
+	default:
+	}
+
+	select {
+	case jobs <- i:
+		return true
+	case <-stop:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// skipped returns the result for a cluster which wasn't operated on.
+func (b *bulkRun) skipped(ctx context.Context, slug string) BulkClusterResult {
+	if err := ctx.Err(); err != nil {
+		return BulkClusterResult{Slug: slug, Err: fmt.Errorf("%w: %w", ErrBulkSkipped, err)}
+	}
+	return BulkClusterResult{Slug: slug, Err: fmt.Errorf("%w after an earlier failure", ErrBulkSkipped)}
+}
+
+// apply operates on the cluster associated with slug.
+func (b *bulkRun) apply(ctx context.Context, slug string) BulkClusterResult {
+	result := BulkClusterResult{Slug: slug}
+
+	switch b.opt.Action {
+	case BulkActionUpdate:
+		opt := b.opt.Update
+		if opt.Name == "" {
+			cluster, err := b.client.GetBySlug(ctx, slug)
+			if err != nil {
+				result.Err = fmt.Errorf("retrieving current name: %w", err)
+				return result
+			}
+			opt.Name = cluster.Name
+		}
+
+		updated, err := b.client.Update(ctx, slug, opt)
+		result.Message, result.Monitor, result.Err = updated.Message, updated.Monitor, err
+	case BulkActionDestroy:
+		destroyed, err := b.client.Destroy(ctx, slug)
+		result.Message, result.Monitor, result.Err = destroyed.Message, destroyed.Monitor, err
+	}
+
+	return result
+}
@@ -0,0 +1,282 @@
+package bonsai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/time/rate"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/bonsaitest"
+)
+
+type BulkClustersTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all HTTP request tests
+	suite.Suite
+
+	// server is the fake API server, seeded with bulkClusterCount clusters
+	server *bonsaitest.Server
+	// client is wired to make requests against server
+	client *bonsai.Client
+	// slugs of the clusters the server is seeded with
+	slugs []string
+}
+
+const bulkClusterCount = 6
+
+func (s *BulkClustersTestSuite) SetupTest() {
+	fixtures := bonsaitest.DefaultFixtures()
+	s.slugs = nil
+	for i := range bulkClusterCount {
+		slug := fmt.Sprintf("bulk-cluster-%d-1234567890", i)
+		s.slugs = append(s.slugs, slug)
+		fixtures.Clusters = append(fixtures.Clusters, bonsai.Cluster{
+			Slug:  slug,
+			Name:  fmt.Sprintf("bulk_cluster_%d", i),
+			Plan:  bonsai.Plan{Slug: "sandbox-aws-us-east-1"},
+			Space: bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+			State: bonsai.ClusterStateProvisioned,
+		})
+	}
+
+	s.server = bonsaitest.NewServer(bonsaitest.WithFixtures(fixtures))
+	s.client = s.server.Client()
+
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func (s *BulkClustersTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestBulkClustersTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkClustersTestSuite))
+}
+
+// collect receives progress until the channel is closed, returning it
+// once done.
+func collect(progress <-chan bonsai.BulkClustersProgress) func() []bonsai.BulkClustersProgress {
+	var (
+		wg     sync.WaitGroup
+		events []bonsai.BulkClustersProgress
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for p := range progress {
+			events = append(events, p)
+		}
+	}()
+
+	return func() []bonsai.BulkClustersProgress {
+		wg.Wait()
+		return events
+	}
+}
+
+func (s *BulkClustersTestSuite) TestUpdate() {
+	progress := make(chan bonsai.BulkClustersProgress)
+	events := collect(progress)
+
+	results, err := s.client.Cluster.Bulk(context.Background(), s.slugs, bonsai.BulkClustersOpts{
+		Action:   bonsai.BulkActionUpdate,
+		Update:   bonsai.ClusterUpdateOpts{Plan: "standard-sm"},
+		Progress: progress,
+	})
+	s.NoError(err)
+	s.Len(results, bulkClusterCount)
+
+	for i, result := range results {
+		s.Equal(s.slugs[i], result.Slug, "results are in the order of the slugs")
+		s.NoError(result.Err)
+		s.False(result.Skipped())
+		s.Equal("Your cluster is being updated.", result.Message)
+
+		cluster, ok := s.server.Cluster(result.Slug)
+		s.True(ok)
+		s.Equal("standard-sm", cluster.Plan.Slug)
+		s.Equal(fmt.Sprintf("bulk_cluster_%d", i), cluster.Name, "clusters keep their current names")
+	}
+
+	received := events()
+	s.Len(received, bulkClusterCount, "progress is reported for each cluster")
+	for i, p := range received {
+		s.Equal(i+1, p.Done)
+		s.Equal(bulkClusterCount, p.Total)
+		s.Zero(p.Failed)
+		s.Zero(p.Skipped)
+	}
+}
+
+func (s *BulkClustersTestSuite) TestDestroyBestEffort() {
+	failing := s.slugs[2]
+	s.server.FailNext(http.MethodDelete, bonsai.ClusterAPIBasePath+"/"+failing, http.StatusForbidden, "Nope.")
+
+	progress := make(chan bonsai.BulkClustersProgress, bulkClusterCount)
+	results, err := s.client.Cluster.Bulk(context.Background(), s.slugs, bonsai.BulkClustersOpts{
+		Action:      bonsai.BulkActionDestroy,
+		Concurrency: 2,
+		Progress:    progress,
+	})
+	s.ErrorIs(err, bonsai.ErrHTTPStatusForbidden)
+	s.ErrorContains(err, "cluster "+failing)
+	s.Len(results, bulkClusterCount)
+
+	for i, result := range results {
+		cluster, ok := s.server.Cluster(result.Slug)
+		s.True(ok)
+
+		if i == 2 {
+			s.ErrorIs(result.Err, bonsai.ErrHTTPStatusForbidden)
+			s.Equal(bonsai.ClusterStateProvisioned, cluster.State)
+			continue
+		}
+		s.NoError(result.Err, "clusters are operated on regardless of failures")
+		s.Equal(bonsai.ClusterStateDeprovisioning, cluster.State)
+	}
+
+	var last bonsai.BulkClustersProgress
+	for p := range progress {
+		last = p
+	}
+	s.Equal(bonsai.BulkClustersProgress{
+		Result: last.Result,
+		Done:   bulkClusterCount,
+		Failed: 1,
+		Total:  bulkClusterCount,
+	}, last, "progress is closed once done")
+}
+
+func (s *BulkClustersTestSuite) TestStopOnError() {
+	failing := s.slugs[1]
+	s.server.FailNext(http.MethodDelete, bonsai.ClusterAPIBasePath+"/"+failing, http.StatusForbidden)
+
+	progress := make(chan bonsai.BulkClustersProgress)
+	events := collect(progress)
+
+	results, err := s.client.Cluster.Bulk(context.Background(), s.slugs, bonsai.BulkClustersOpts{
+		Action:      bonsai.BulkActionDestroy,
+		Concurrency: 1,
+		Mode:        bonsai.BulkStopOnError,
+		Progress:    progress,
+	})
+	s.ErrorIs(err, bonsai.ErrHTTPStatusForbidden)
+	s.NotErrorIs(err, bonsai.ErrBulkSkipped, "skipped clusters aren't errors themselves")
+
+	s.NoError(results[0].Err)
+	s.ErrorIs(results[1].Err, bonsai.ErrHTTPStatusForbidden)
+	for _, result := range results[2:] {
+		s.True(result.Skipped(), "%s is skipped", result.Slug)
+		s.ErrorIs(result.Err, bonsai.ErrBulkSkipped)
+
+		cluster, ok := s.server.Cluster(result.Slug)
+		s.True(ok)
+		s.Equal(bonsai.ClusterStateProvisioned, cluster.State)
+	}
+
+	received := events()
+	s.Len(received, bulkClusterCount, "progress is reported for skipped clusters")
+	s.Equal(1, received[len(received)-1].Failed)
+	s.Equal(bulkClusterCount-2, received[len(received)-1].Skipped)
+}
+
+func (s *BulkClustersTestSuite) TestConcurrency() {
+	const concurrency = 2
+
+	var (
+		inFlight, maxInFlight atomic.Int32
+		// limiter allows exactly one request per cluster, without refilling
+		limiter = rate.NewLimiter(rate.Every(time.Hour), bulkClusterCount)
+	)
+	client := s.server.Client(
+		bonsai.WithDefaultRateLimit(limiter),
+		bonsai.WithMiddleware(func(next bonsai.Doer) bonsai.Doer {
+			return bonsai.DoerFunc(func(ctx context.Context, req *http.Request) (*bonsai.Response, error) {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					highest := maxInFlight.Load()
+					if n <= highest || maxInFlight.CompareAndSwap(highest, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				return next.Do(ctx, req)
+			})
+		}),
+	)
+
+	_, err := client.Cluster.Bulk(context.Background(), s.slugs, bonsai.BulkClustersOpts{
+		Action:      bonsai.BulkActionDestroy,
+		Concurrency: concurrency,
+	})
+	s.NoError(err)
+	s.Equal(int32(concurrency), maxInFlight.Load(), "clusters are operated on concurrently, up to the limit")
+	s.Less(limiter.Tokens(), 1.0, "requests wait on the client's rate limiter")
+}
+
+func (s *BulkClustersTestSuite) TestCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	progress := make(chan bonsai.BulkClustersProgress, bulkClusterCount)
+	results, err := s.client.Cluster.Bulk(ctx, s.slugs, bonsai.BulkClustersOpts{
+		Action:   bonsai.BulkActionDestroy,
+		Progress: progress,
+	})
+	s.ErrorIs(err, context.Canceled)
+	for _, result := range results {
+		s.True(result.Skipped())
+		s.ErrorIs(result.Err, context.Canceled)
+	}
+	s.Empty(s.server.Requests())
+	s.Len(progress, bulkClusterCount)
+}
+
+func (s *BulkClustersTestSuite) TestInvalid() {
+	testCases := []struct {
+		name  string
+		slugs []string
+		opt   bonsai.BulkClustersOpts
+	}{
+		{
+			name:  "missing action",
+			slugs: s.slugs,
+			opt:   bonsai.BulkClustersOpts{},
+		},
+		{
+			name:  "unknown mode",
+			slugs: s.slugs,
+			opt:   bonsai.BulkClustersOpts{Action: bonsai.BulkActionDestroy, Mode: 7},
+		},
+		{
+			name:  "duplicate slugs",
+			slugs: []string{s.slugs[0], s.slugs[0]},
+			opt:   bonsai.BulkClustersOpts{Action: bonsai.BulkActionDestroy},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			progress := make(chan bonsai.BulkClustersProgress)
+			tc.opt.Progress = progress
+
+			_, err := s.client.Cluster.Bulk(context.Background(), tc.slugs, tc.opt)
+			s.Error(err)
+
+			_, open := <-progress
+			s.False(open, "progress is closed")
+		})
+	}
+	s.Empty(s.server.Requests())
+}
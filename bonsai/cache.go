@@ -0,0 +1,291 @@
+package bonsai
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Response cache defaults.
+const (
+	// DefaultCacheSize is the default number of responses held by a
+	// MemoryCache.
+	DefaultCacheSize = 256
+	// DefaultCacheTTL is the default duration for which a MemoryCache holds
+	// responses before they're revalidated or fetched again.
+	DefaultCacheTTL = 5 * time.Minute
+)
+
+// Cache validation headers.
+const (
+	HeaderETag        = "ETag"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// CacheEntry is a response held by a Cache.
+type CacheEntry struct {
+	// Header holds the response headers.
+	Header http.Header
+	// Body holds the response body.
+	Body []byte
+	// Expires is the time after which the entry is stale, and must be
+	// revalidated or fetched again. It's assigned by the Cache when the
+	// entry is stored.
+	Expires time.Time
+}
+
+// ETag returns the entity tag the server assigned to the response, if any.
+func (e CacheEntry) ETag() string {
+	return e.Header.Get(HeaderETag)
+}
+
+// Fresh reports whether the entry may be used, as of now, without
+// revalidation.
+func (e CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+// Cache holds responses to GET requests for Plans, Spaces and Releases,
+// keyed by request URL; see WithCache.
+//
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry stored under key, if any. Stale entries may be
+	// returned, such that they can be revalidated by their ETag.
+	Get(key string) (CacheEntry, bool)
+	// Set stores entry under key, assigning its Expires time.
+	Set(key string, entry CacheEntry)
+	// Invalidate removes all entries whose keys begin with prefix.
+	Invalidate(prefix string)
+}
+
+// WithCache configures a Client to serve GET requests for Plans, Spaces
+// and Releases from cache, which change rarely, sparing the Client's rate
+// limit. Stale responses are revalidated with If-None-Match when the API
+// provided an ETag for them.
+//
+// Responses served from cache don't pass through the Client's middleware.
+// As the catalog may differ between accounts, a Cache shouldn't be shared
+// between Clients using different credentials.
+//
+//	client := bonsai.NewClient(
+//		bonsai.WithCache(bonsai.NewMemoryCache(bonsai.DefaultCacheSize, bonsai.DefaultCacheTTL)),
+//	)
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// InvalidateCache removes the responses held by the Client's Cache, if any,
+// for the given base paths, such as PlanAPIBasePath. If no paths are given,
+// all of the Client's responses are removed.
+func (c *Client) InvalidateCache(basePaths ...string) {
+	if c.cache == nil {
+		return
+	}
+
+	if len(basePaths) == 0 {
+		c.cache.Invalidate(c.endpoint + "/")
+		return
+	}
+	for _, p := range basePaths {
+		c.cache.Invalidate(c.endpoint + p)
+	}
+}
+
+// cacheable reports whether the response to req may be cached.
+func (c *Client) cacheable(req *http.Request) bool {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return false
+	}
+
+	p := c.apiPath(req)
+	for _, base := range []string{PlanAPIBasePath, SpaceAPIBasePath, ReleaseAPIBasePath} {
+		if p == base || strings.HasPrefix(p, base+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// doCached performs req per Do, serving its response from the Client's
+// Cache while fresh, and revalidating it once stale.
+func (c *Client) doCached(ctx context.Context, req *http.Request) (*Response, error) {
+	key := req.URL.String()
+
+	entry, cached := c.cache.Get(key)
+	if cached && entry.Fresh(time.Now()) {
+		return entry.response(req)
+	}
+	if cached && entry.ETag() != "" {
+		req.Header.Set(HeaderIfNoneMatch, entry.ETag())
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	switch {
+	case cached && resp.StatusCode == http.StatusNotModified:
+		c.cache.Set(key, entry)
+		return entry.response(req)
+	case resp.StatusCode == http.StatusOK:
+		c.cache.Set(key, CacheEntry{
+			Header: resp.Header.Clone(),
+			Body:   bytes.Clone(resp.BodyBuf.Bytes()),
+		})
+	}
+
+	return resp, nil
+}
+
+// response returns a Response replaying the cached response to req.
+func (e CacheEntry) response(req *http.Request) (*Response, error) {
+	resp, err := NewResponse()
+	if err != nil {
+		return resp, fmt.Errorf("creating new Response: %w", err)
+	}
+
+	err = resp.WithHTTPResponse(&http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("setting http response: %w", err)
+	}
+
+	if resp.isJSON() {
+		if err = json.Unmarshal(resp.BodyBuf.Bytes(), &resp); err != nil {
+			return resp, fmt.Errorf("error unmarshaling cached response body for pagination: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// MemoryCache is an in-memory Cache, holding a bounded number of responses
+// for a fixed duration. Once full, the least recently used responses are
+// evicted.
+//
+// Stale responses are retained for revalidation if they have an ETag, and
+// evicted otherwise.
+type MemoryCache struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru orders entries from most to least recently used.
+	lru *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewMemoryCache returns a MemoryCache holding up to size responses, each
+// for ttl. Values of zero or below select DefaultCacheSize and
+// DefaultCacheTTL.
+func NewMemoryCache(size int, ttl time.Duration) *MemoryCache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &MemoryCache{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+
+	item := itemOf(elem)
+	if !item.entry.Fresh(m.now()) && item.entry.ETag() == "" {
+		m.remove(elem)
+		return CacheEntry{}, false
+	}
+
+	m.lru.MoveToFront(elem)
+	return item.entry, true
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(key string, entry CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry.Expires = m.now().Add(m.ttl)
+
+	if elem, ok := m.entries[key]; ok {
+		itemOf(elem).entry = entry
+		m.lru.MoveToFront(elem)
+		return
+	}
+
+	m.entries[key] = m.lru.PushFront(&memoryCacheItem{key: key, entry: entry})
+	for m.lru.Len() > m.size {
+		m.remove(m.lru.Back())
+	}
+}
+
+// Invalidate implements Cache.
+func (m *MemoryCache) Invalidate(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, elem := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(elem)
+		}
+	}
+}
+
+// Len returns the number of responses held.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.lru.Len()
+}
+
+func (m *MemoryCache) remove(elem *list.Element) {
+	m.lru.Remove(elem)
+	delete(m.entries, itemOf(elem).key)
+}
+
+func itemOf(elem *list.Element) *memoryCacheItem {
+	item, _ := elem.Value.(*memoryCacheItem)
+	return item
+}
@@ -0,0 +1,88 @@
+package bonsai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type MemoryCacheTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all tests
+	suite.Suite
+}
+
+func (s *MemoryCacheTestSuite) SetupTest() {
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func TestMemoryCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(MemoryCacheTestSuite))
+}
+
+func (s *MemoryCacheTestSuite) TestEviction() {
+	cache := NewMemoryCache(2, time.Minute)
+
+	cache.Set("a", CacheEntry{Body: []byte("a")})
+	cache.Set("b", CacheEntry{Body: []byte("b")})
+	_, ok := cache.Get("a")
+	s.True(ok)
+
+	cache.Set("c", CacheEntry{Body: []byte("c")})
+	s.Equal(2, cache.Len())
+
+	_, ok = cache.Get("b")
+	s.False(ok, "the least recently used entry is evicted")
+	_, ok = cache.Get("a")
+	s.True(ok)
+	_, ok = cache.Get("c")
+	s.True(ok)
+}
+
+func (s *MemoryCacheTestSuite) TestExpiry() {
+	now := time.Now()
+	cache := NewMemoryCache(0, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("plain", CacheEntry{Body: []byte("plain")})
+	header := http.Header{}
+	header.Set(HeaderETag, `"v1"`)
+	cache.Set("tagged", CacheEntry{Header: header, Body: []byte("tagged")})
+
+	entry, ok := cache.Get("plain")
+	s.True(ok)
+	s.True(entry.Fresh(now))
+	s.Equal(now.Add(time.Minute), entry.Expires)
+
+	now = now.Add(2 * time.Minute)
+
+	_, ok = cache.Get("plain")
+	s.False(ok, "stale entries without an ETag are evicted")
+
+	entry, ok = cache.Get("tagged")
+	s.True(ok, "stale entries with an ETag are retained for revalidation")
+	s.False(entry.Fresh(now))
+	s.Equal(`"v1"`, entry.ETag())
+
+	cache.Set("tagged", entry)
+	entry, ok = cache.Get("tagged")
+	s.True(ok)
+	s.True(entry.Fresh(now), "revalidated entries are fresh again")
+}
+
+func (s *MemoryCacheTestSuite) TestInvalidate() {
+	cache := NewMemoryCache(0, 0)
+	cache.Set("https://api.bonsai.io/plans", CacheEntry{})
+	cache.Set("https://api.bonsai.io/plans/standard-sm", CacheEntry{})
+	cache.Set("https://api.bonsai.io/spaces", CacheEntry{})
+
+	cache.Invalidate("https://api.bonsai.io/plans")
+	s.Equal(1, cache.Len())
+	_, ok := cache.Get("https://api.bonsai.io/spaces")
+	s.True(ok)
+}
@@ -0,0 +1,140 @@
+package bonsai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// staleCache is a Cache whose entries are always stale, such that each
+// request is revalidated.
+type staleCache struct {
+	mu      sync.Mutex
+	entries map[string]bonsai.CacheEntry
+}
+
+func (c *staleCache) Get(key string) (bonsai.CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *staleCache) Set(key string, entry bonsai.CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+func (c *staleCache) Invalidate(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (s *ClientMockTestSuite) TestClient_WithCache() {
+	const prefix = "/cache"
+	ctx := context.Background()
+
+	var planCalls, clusterCalls atomic.Int32
+	s.serveMux.Get(prefix+bonsai.PlanAPIBasePath+"/{slug}", func(w http.ResponseWriter, _ *http.Request) {
+		planCalls.Add(1)
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		_, err := fmt.Fprint(w, `{"slug": "standard-sm", "name": "Standard Small"}`)
+		s.NoError(err, "wrote plan response")
+	})
+	s.serveMux.Get(prefix+bonsai.ClusterAPIBasePath+"/{slug}", func(w http.ResponseWriter, _ *http.Request) {
+		clusterCalls.Add(1)
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		_, err := fmt.Fprint(w, `{"slug": "cluster-1234", "name": "cluster"}`)
+		s.NoError(err, "wrote cluster response")
+	})
+
+	cache := bonsai.NewMemoryCache(0, 0)
+	client := bonsai.NewClient(bonsai.WithEndpoint(s.server.URL+prefix), bonsai.WithCache(cache))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			plan, err := client.Plan.GetBySlug(ctx, "standard-sm")
+			s.NoError(err)
+			s.Equal("Standard Small", plan.Name)
+		}()
+	}
+	wg.Wait()
+
+	plan, err := client.Plan.GetBySlug(ctx, "standard-sm")
+	s.NoError(err)
+	s.Equal("Standard Small", plan.Name)
+	s.LessOrEqual(planCalls.Load(), int32(10), "plans are served from cache once stored")
+	s.Equal(1, cache.Len())
+	calls := planCalls.Load()
+
+	_, err = client.Plan.GetBySlug(ctx, "standard-sm")
+	s.NoError(err)
+	s.Equal(calls, planCalls.Load(), "plans are served from cache")
+
+	for range 2 {
+		_, err = client.Cluster.GetBySlug(ctx, "cluster-1234")
+		s.NoError(err)
+	}
+	s.Equal(int32(2), clusterCalls.Load(), "clusters aren't cached")
+
+	client.InvalidateCache(bonsai.SpaceAPIBasePath)
+	s.Equal(1, cache.Len(), "other resources are retained")
+
+	client.Catalog.Invalidate()
+	s.Zero(cache.Len(), "plans are discarded with the catalog")
+
+	_, err = client.Plan.GetBySlug(ctx, "standard-sm")
+	s.NoError(err)
+	s.Equal(calls+1, planCalls.Load(), "invalidated plans are fetched again")
+
+	client.InvalidateCache()
+	s.Zero(cache.Len())
+}
+
+func (s *ClientMockTestSuite) TestClient_WithCache_Revalidation() {
+	const (
+		prefix = "/cache-revalidation"
+		etag   = `"v1"`
+	)
+	ctx := context.Background()
+
+	var requests []string
+	s.serveMux.Get(prefix+bonsai.SpaceAPIBasePath+"/*", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Get(bonsai.HeaderIfNoneMatch))
+		if r.Header.Get(bonsai.HeaderIfNoneMatch) == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		w.Header().Set(bonsai.HeaderETag, etag)
+		_, err := fmt.Fprint(w, `{"path": "omc/bonsai/us-east-1/common", "region": "aws-us-east-1"}`)
+		s.NoError(err, "wrote space response")
+	})
+
+	client := bonsai.NewClient(
+		bonsai.WithEndpoint(s.server.URL+prefix),
+		bonsai.WithCache(&staleCache{entries: map[string]bonsai.CacheEntry{}}),
+	)
+
+	for range 3 {
+		space, err := client.Space.GetByPath(ctx, "omc/bonsai/us-east-1/common")
+		s.NoError(err)
+		s.Equal("omc/bonsai/us-east-1/common", space.Path, "not modified responses are served from cache")
+	}
+
+	s.Equal([]string{"", etag, etag}, requests, "stale responses are revalidated by their ETag")
+}
@@ -0,0 +1,254 @@
+package bonsai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCatalogTTL is the default duration for which a Catalog holds
+// results before they're fetched again.
+const DefaultCatalogTTL = 15 * time.Minute
+
+var (
+	// ErrUnknownPlan is returned when a plan slug isn't found in the catalog.
+	ErrUnknownPlan = errors.New("plan not found in catalog")
+	// ErrSpaceUnavailable is returned when a space isn't available for a plan.
+	ErrSpaceUnavailable = errors.New("space not available for plan")
+	// ErrReleaseUnavailable is returned when a release isn't available for a plan.
+	ErrReleaseUnavailable = errors.New("release not available for plan")
+)
+
+// CatalogError describes a plan, space or release which the catalog
+// doesn't offer, along with the alternatives that it does.
+type CatalogError struct {
+	// Plan is the slug of the plan the value was checked against, if any.
+	Plan string
+	// Available holds the values the catalog offers in place of the
+	// rejected value.
+	Available []string
+	// Err is one of ErrUnknownPlan, ErrSpaceUnavailable or
+	// ErrReleaseUnavailable.
+	Err error
+}
+
+func (e CatalogError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+	if e.Plan != "" {
+		fmt.Fprintf(&b, " %q", e.Plan)
+	}
+	if len(e.Available) > 0 {
+		fmt.Fprintf(&b, " (available: %s)", strings.Join(e.Available, ", "))
+	}
+	return b.String()
+}
+
+func (e CatalogError) Unwrap() error {
+	return e.Err
+}
+
+// Catalog caches the Plans offered by the API, so that requests can be
+// validated client-side before they're sent.
+//
+// A Catalog is safe for concurrent use.
+type Catalog struct {
+	client *Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	// mu guards the entries; it's never held while fetching.
+	mu       sync.Mutex
+	plans    catalogEntry
+	expanded catalogEntry
+}
+
+// catalogEntry holds the cached results of a single kind of fetch.
+type catalogEntry struct {
+	plans     []Plan
+	fetchedAt time.Time
+	// fetch is the fetch in flight, if any, which is shared by concurrent
+	// callers.
+	fetch *catalogFetch
+}
+
+// catalogFetch is the result of a fetch, available once done is closed.
+type catalogFetch struct {
+	done  chan struct{}
+	plans []Plan
+	err   error
+}
+
+func newCatalog(client *Client, ttl time.Duration) *Catalog {
+	return &Catalog{
+		client: client,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// WithCatalogTTL configures how long the Client's Catalog holds results
+// before they're fetched again. A TTL of zero or below disables caching.
+func WithCatalogTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.catalogTTL = ttl
+	}
+}
+
+// WithCreateValidation configures whether ClusterClient.Create validates
+// its options against the Client's Catalog before making a request; see
+// [Catalog.ValidateCreate]. Disabled by default.
+func WithCreateValidation(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.validateCreate = enabled
+	}
+}
+
+// Invalidate discards the cached results, such that they're fetched again
+// on next use.
+//
+// Responses for Plans, Releases and Spaces held by the Client's Cache, if
+// any, are also discarded.
+func (c *Catalog) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.plans = catalogEntry{}
+	c.expanded = catalogEntry{}
+	c.client.InvalidateCache(PlanAPIBasePath, ReleaseAPIBasePath, SpaceAPIBasePath)
+}
+
+// AllPlans returns all Plans, from the cache if its results haven't
+// expired.
+func (c *Catalog) AllPlans(ctx context.Context) ([]Plan, error) {
+	plans, err := c.load(ctx, &c.plans, c.client.Plan.All)
+	if err != nil {
+		return nil, fmt.Errorf("fetching plans catalog: %w", err)
+	}
+	return plans, nil
+}
+
+// AllPlansExpanded returns all Plans, with their AvailableReleases and
+// AvailableSpaces resolved per PlanClient.AllExpanded, from the cache if its
+// results haven't expired.
+func (c *Catalog) AllPlansExpanded(ctx context.Context) ([]Plan, error) {
+	plans, err := c.load(ctx, &c.expanded, c.client.Plan.AllExpanded)
+	if err != nil {
+		return nil, fmt.Errorf("fetching expanded plans catalog: %w", err)
+	}
+	return plans, nil
+}
+
+// load returns a copy of the results held by entry, unless they've expired,
+// in which case they're fetched again. Concurrent callers share a single
+// fetch, which is made without holding c.mu.
+func (c *Catalog) load(
+	ctx context.Context,
+	entry *catalogEntry,
+	fetch func(context.Context) ([]Plan, error),
+) ([]Plan, error) {
+	for {
+		c.mu.Lock()
+		if entry.plans != nil && c.now().Sub(entry.fetchedAt) < c.ttl {
+			plans := slices.Clone(entry.plans)
+			c.mu.Unlock()
+			return plans, nil
+		}
+
+		if f := entry.fetch; f != nil {
+			c.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// A fetch abandoned by its caller is retried with ours.
+			if f.err != nil && ctx.Err() == nil &&
+				(errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) {
+				continue
+			}
+			return slices.Clone(f.plans), f.err
+		}
+
+		f := &catalogFetch{done: make(chan struct{})}
+		entry.fetch = f
+		c.mu.Unlock()
+
+		f.plans, f.err = fetch(ctx)
+
+		c.mu.Lock()
+		// Results fetched before a call to Invalidate aren't cached; it
+		// replaces entry.fetch.
+		if entry.fetch == f {
+			entry.fetch = nil
+			if f.err == nil {
+				entry.plans, entry.fetchedAt = f.plans, c.now()
+			}
+		}
+		c.mu.Unlock()
+		close(f.done)
+
+		return slices.Clone(f.plans), f.err
+	}
+}
+
+// ValidateCreate checks that the plan requested by opt is offered by the
+// catalog, and that the requested space and release are available for it.
+// Spaces and releases can't be checked when no plan is requested.
+//
+// Invalid values are reported as an OptionError, wrapping a CatalogError.
+func (c *Catalog) ValidateCreate(ctx context.Context, opt ClusterCreateOpts) error {
+	if opt.Plan == "" {
+		return nil
+	}
+
+	plans, err := c.AllPlans(ctx)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(plans, func(p Plan) bool { return p.Slug == opt.Plan })
+	if i < 0 {
+		available := make([]string, len(plans))
+		for j, p := range plans {
+			available[j] = p.Slug
+		}
+		return OptionError{Field: "plan", Value: opt.Plan, Err: CatalogError{Available: available, Err: ErrUnknownPlan}}
+	}
+	plan := plans[i]
+
+	var errs []error
+	if opt.Space != "" {
+		available := make([]string, len(plan.AvailableSpaces))
+		for j, s := range plan.AvailableSpaces {
+			available[j] = s.Path
+		}
+		if !slices.Contains(available, opt.Space) {
+			errs = append(errs, OptionError{
+				Field: "space",
+				Value: opt.Space,
+				Err:   CatalogError{Plan: plan.Slug, Available: available, Err: ErrSpaceUnavailable},
+			})
+		}
+	}
+
+	if opt.Release != "" {
+		available := make([]string, len(plan.AvailableReleases))
+		for j, r := range plan.AvailableReleases {
+			available[j] = r.Slug
+		}
+		if !slices.Contains(available, opt.Release) {
+			errs = append(errs, OptionError{
+				Field: "release",
+				Value: opt.Release,
+				Err:   CatalogError{Plan: plan.Slug, Available: available, Err: ErrReleaseUnavailable},
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package bonsai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func (s *ClientImplTestSuite) TestCatalogTTL() {
+	const prefix = "/catalog-ttl"
+
+	requests := 0
+	s.serveMux.Get(prefix+PlanAPIBasePath, func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.Header().Set(HTTPHeaderContentType, HTTPContentTypeJSON)
+		_, err := fmt.Fprint(w, `{"plans": [{"slug": "sandbox-aws-us-east-1"}]}`)
+		s.NoError(err, "wrote plans response")
+	})
+
+	now := time.Now()
+	client := NewClient(WithEndpoint(s.server.URL+prefix), WithCatalogTTL(time.Minute))
+	client.Catalog.now = func() time.Time { return now }
+
+	for range 2 {
+		plans, err := client.Catalog.AllPlans(context.Background())
+		s.NoError(err)
+		s.Len(plans, 1)
+	}
+	s.Equal(1, requests, "cached results are reused within the TTL")
+
+	now = now.Add(time.Minute)
+	_, err := client.Catalog.AllPlans(context.Background())
+	s.NoError(err)
+	s.Equal(2, requests, "results are fetched again once the TTL expires")
+
+	client = NewClient(WithEndpoint(s.server.URL+prefix), WithCatalogTTL(0))
+	for range 2 {
+		_, err = client.Catalog.AllPlans(context.Background())
+		s.NoError(err)
+	}
+	s.Equal(4, requests, "caching is disabled without a TTL")
+}
+
+func (s *ClientImplTestSuite) TestCatalogConcurrentFetch() {
+	const prefix = "/catalog-concurrent"
+
+	var (
+		requests atomic.Int32
+		started  = make(chan struct{}, 2)
+		release  = make(chan struct{})
+	)
+	s.serveMux.Get(prefix+PlanAPIBasePath, func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		started <- struct{}{}
+		<-release
+		w.Header().Set(HTTPHeaderContentType, HTTPContentTypeJSON)
+		_, err := fmt.Fprint(w, `{"plans": [{"slug": "sandbox-aws-us-east-1"}]}`)
+		s.NoError(err, "wrote plans response")
+	})
+
+	client := NewClient(WithEndpoint(s.server.URL+prefix), WithCatalogTTL(time.Minute))
+
+	var wg sync.WaitGroup
+	fetch := func() {
+		defer wg.Done()
+		plans, err := client.Catalog.AllPlans(context.Background())
+		s.NoError(err)
+		s.Len(plans, 1)
+	}
+
+	wg.Add(2)
+	go fetch()
+	<-started
+	go fetch()
+	// Invalidate doesn't wait on the fetch in flight.
+	client.Catalog.Invalidate()
+	close(release)
+	wg.Wait()
+
+	plans, err := client.Catalog.AllPlans(context.Background())
+	s.NoError(err)
+	s.Equal(int32(2), requests.Load(), "results fetched before Invalidate aren't cached")
+
+	plans[0].Slug = "modified"
+	plans, err = client.Catalog.AllPlans(context.Background())
+	s.NoError(err)
+	s.Equal("sandbox-aws-us-east-1", plans[0].Slug, "cached results are copied")
+	s.Equal(int32(2), requests.Load())
+}
@@ -0,0 +1,143 @@
+package bonsai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+const catalogPlansResponse = `
+	{
+		"plans": [
+			{
+				"slug": "sandbox-aws-us-east-1",
+				"name": "Sandbox",
+				"available_releases": ["opensearch-2.6.0-mt"],
+				"available_spaces": ["omc/bonsai/us-east-1/common"]
+			},
+			{
+				"slug": "standard-sm",
+				"name": "Standard Small",
+				"available_releases": ["elasticsearch-7.10.2", "opensearch-2.6.0-mt"],
+				"available_spaces": ["omc/bonsai/us-east-1/common", "omc/bonsai/eu-west-1/common"]
+			}
+		]
+	}`
+
+func (s *ClientMockTestSuite) TestClusterClient_CreateValidation() {
+	const prefix = "/create-validation"
+
+	var planRequests, createRequests int
+	s.serveMux.Get(prefix+bonsai.PlanAPIBasePath, func(w http.ResponseWriter, _ *http.Request) {
+		planRequests++
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		_, err := fmt.Fprint(w, catalogPlansResponse)
+		s.NoError(err, "wrote plans response")
+	})
+	s.serveMux.Post(prefix+bonsai.ClusterAPIBasePath, func(w http.ResponseWriter, _ *http.Request) {
+		createRequests++
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		w.WriteHeader(http.StatusAccepted)
+		_, err := fmt.Fprint(w, `{"message": "Your cluster is being provisioned.", "monitor": "https://api.bonsai.io/clusters/test-1234"}`)
+		s.NoError(err, "wrote create response")
+	})
+
+	client := bonsai.NewClient(
+		bonsai.WithEndpoint(s.server.URL+prefix),
+		bonsai.WithCreateValidation(true),
+	)
+
+	testCases := []struct {
+		name        string
+		opt         bonsai.ClusterCreateOpts
+		expectErrs  []error
+		expectField string
+	}{
+		{
+			name: "valid combination",
+			opt: bonsai.ClusterCreateOpts{
+				Name:    "test",
+				Plan:    "standard-sm",
+				Space:   "omc/bonsai/eu-west-1/common",
+				Release: "elasticsearch-7.10.2",
+			},
+		},
+		{
+			name: "no plan",
+			opt:  bonsai.ClusterCreateOpts{Name: "test", Space: "omc/bonsai/ap-southeast-2/common"},
+		},
+		{
+			name:        "unknown plan",
+			opt:         bonsai.ClusterCreateOpts{Name: "test", Plan: "enterprise-xl"},
+			expectErrs:  []error{bonsai.ErrInvalidOption, bonsai.ErrUnknownPlan},
+			expectField: "plan",
+		},
+		{
+			name: "space unavailable for plan",
+			opt: bonsai.ClusterCreateOpts{
+				Name:  "test",
+				Plan:  "sandbox-aws-us-east-1",
+				Space: "omc/bonsai/eu-west-1/common",
+			},
+			expectErrs:  []error{bonsai.ErrInvalidOption, bonsai.ErrSpaceUnavailable},
+			expectField: "space",
+		},
+		{
+			name: "release unavailable for plan",
+			opt: bonsai.ClusterCreateOpts{
+				Name:    "test",
+				Plan:    "sandbox-aws-us-east-1",
+				Release: "elasticsearch-7.10.2",
+			},
+			expectErrs:  []error{bonsai.ErrInvalidOption, bonsai.ErrReleaseUnavailable},
+			expectField: "release",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			before := createRequests
+
+			_, err := client.Cluster.Create(context.Background(), tc.opt)
+			if len(tc.expectErrs) == 0 {
+				s.NoError(err)
+				s.Equal(before+1, createRequests, "create request sent")
+				return
+			}
+
+			for _, expectErr := range tc.expectErrs {
+				s.ErrorIs(err, expectErr)
+			}
+
+			optErr := bonsai.OptionError{}
+			s.True(errors.As(err, &optErr))
+			s.Equal(tc.expectField, optErr.Field)
+
+			catalogErr := bonsai.CatalogError{}
+			s.True(errors.As(err, &catalogErr))
+			s.NotEmpty(catalogErr.Available)
+
+			s.Equal(before, createRequests, "create request not sent")
+		})
+	}
+
+	s.Equal(1, planRequests, "plans catalog is cached between requests")
+
+	client.Catalog.Invalidate()
+	_, err := client.Catalog.AllPlans(context.Background())
+	s.NoError(err)
+	s.Equal(2, planRequests, "plans catalog is fetched again once invalidated")
+}
+
+func (s *ClientMockTestSuite) TestCatalogError() {
+	err := bonsai.CatalogError{
+		Plan:      "sandbox-aws-us-east-1",
+		Available: []string{"omc/bonsai/us-east-1/common"},
+		Err:       bonsai.ErrSpaceUnavailable,
+	}
+	s.EqualError(err, `space not available for plan "sandbox-aws-us-east-1" (available: omc/bonsai/us-east-1/common)`)
+	s.ErrorIs(err, bonsai.ErrSpaceUnavailable)
+}
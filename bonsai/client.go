@@ -47,9 +47,13 @@ const (
 
 // Common API Response headers.
 const (
-	// HeaderRetryAfter holds the number of seconds to delay before making the next request.
+	// HeaderRetryAfter holds the number of seconds, or the HTTP-date after which,
+	// to make the next request.
 	// ref: https://bonsai.io/docs/api-error-429-too-many-requests
 	HeaderRetryAfter = "Retry-After"
+	// HeaderRequestID holds the identifier the API assigned to the request,
+	// which should be included when contacting support about a request.
+	HeaderRequestID = "X-Request-Id"
 )
 
 // HTTP Content Types and related Header.
@@ -72,8 +76,17 @@ var (
 	ErrHTTPStatusUnprocessableEntity = errors.New("unprocessable entity")
 	ErrHTTPStatusUnauthorized        = errors.New("unauthorized")
 	ErrHTTPStatusTooManyRequests     = errors.New("too many requests")
+	ErrHTTPStatusBadRequest          = errors.New("bad request")
+	ErrHTTPStatusConflict            = errors.New("conflict")
+	// ErrHTTPStatusServerError is matched by all 5xx status responses.
+	ErrHTTPStatusServerError = errors.New("server error")
 )
 
+// ErrNetwork is matched by errors from requests which failed before a
+// response was received; for example, due to a refused connection or
+// a timeout.
+var ErrNetwork = errors.New("network error")
+
 var contentTypeRegexp = regexp.MustCompile(fmt.Sprintf("^%s.*", HTTPContentTypeJSON))
 
 // ResponseError captures API response errors
@@ -108,6 +121,14 @@ func (r ResponseError) Is(target error) bool {
 		return target == ErrHTTPStatusUnprocessableEntity
 	case http.StatusTooManyRequests:
 		return target == ErrHTTPStatusTooManyRequests
+	case http.StatusBadRequest:
+		return target == ErrHTTPStatusBadRequest
+	case http.StatusConflict:
+		return target == ErrHTTPStatusConflict
+	}
+
+	if r.Status >= http.StatusInternalServerError {
+		return target == ErrHTTPStatusServerError
 	}
 
 	return false
@@ -133,6 +154,8 @@ func newDefaultListOpts() listOpts {
 // newEmptyListOpts returns an empty list opts,
 // to make it easy for readers to immediately see that there are no options
 // being passed, rather than seeing a struct be initialized in-line.
+//
+//nolint:unused // used by tests exercising Client.all directly
 func newEmptyListOpts() listOpts {
 	return listOpts{}
 }
@@ -235,10 +258,11 @@ func WithEndpoint(endpoint string) ClientOption {
 
 // WithCredentialPair configures a Client to use
 // the specified username for Basic authorization.
+//
+// To pick up rotated credentials without rebuilding the Client, use
+// WithCredentialProvider instead.
 func WithCredentialPair(pair CredentialPair) ClientOption {
-	return func(c *Client) {
-		c.credentialPair = pair
-	}
+	return WithCredentialProvider(pair)
 }
 
 // WithApplication configures the client to represent itself as
@@ -263,6 +287,9 @@ func WithDefaultRateLimit(l *rate.Limiter) ClientOption {
 }
 
 // WithProvisionRateLimit configures the rate limit for client requests to the Provision API.
+//
+// It applies to all POST requests to ClusterAPIBasePath, unless a matching
+// route rate limit has been configured with WithRouteRateLimit.
 func WithProvisionRateLimit(l *rate.Limiter) ClientOption {
 	return func(c *Client) {
 		c.rateLimiter.provisionLimiter = l
@@ -289,6 +316,19 @@ type Response struct {
 	httpResponse      `json:"-"`
 	BodyBuf           bytes.Buffer `json:"-"`
 	PaginatedResponse `json:"pagination"`
+
+	// Attempts is the number of attempts made, per the Client's RetryPolicy,
+	// before this Response was received.
+	Attempts int `json:"-"`
+}
+
+// HTTPResponse returns the *http.Response received from the API, or nil if
+// none was received.
+func (r *Response) HTTPResponse() *http.Response {
+	if r == nil {
+		return nil
+	}
+	return r.httpResponse
 }
 
 func (r *Response) isJSON() bool {
@@ -325,21 +365,6 @@ func (r *Response) readHTTPResponseBody() error {
 	return nil
 }
 
-func extractRetryDelay(r *Response) (int64, error) {
-	var (
-		retryAfter int64
-		err        error
-	)
-	// We're already blocking on this routine, so sleep inline per the header request.
-	if retryAfterStr := r.Header.Get(HeaderRetryAfter); retryAfterStr != "" {
-		retryAfter, err = strconv.ParseInt(retryAfterStr, 10, 64)
-		if err != nil {
-			return retryAfter, fmt.Errorf("error parsing retry-after response: %w", err)
-		}
-	}
-	return retryAfter, nil
-}
-
 // NewResponse reserves this function signature, and is
 // the recommended way to instantiate a Response, as its behavior
 // may change.
@@ -353,6 +378,12 @@ type ClientLimiter struct {
 	limiter
 	// provisionLimiter is the rate limiter to be used for Provision endpoints
 	provisionLimiter *rate.Limiter
+	// routes holds the rate limiters dedicated to specific routes, in order
+	// of precedence.
+	routes []RouteRateLimit
+	// headerSync enables draining the default limiter per the server's
+	// rate limit response headers.
+	headerSync bool
 }
 
 // Client is the exported client that users interact with.
@@ -360,9 +391,19 @@ type Client struct {
 	httpClient *http.Client
 
 	rateLimiter    *ClientLimiter
+	retryPolicy    RetryPolicy
 	endpoint       string
-	credentialPair CredentialPair
+	credentials    CredentialProvider
 	userAgent      string
+	catalogTTL     time.Duration
+	validateCreate bool
+	middleware     []Middleware
+	doer           Doer
+	cache          Cache
+	credentialSink CredentialSink
+
+	// Catalog caches the Plans offered by the API.
+	Catalog *Catalog
 
 	// Clients
 	Space   SpaceClient
@@ -378,13 +419,19 @@ func NewClient(options ...ClientOption) *Client {
 		rateLimiter: &ClientLimiter{
 			limiter:          rate.NewLimiter(rate.Every(DefaultClientBurstDuration), DefaultClientBurstAllowance),
 			provisionLimiter: rate.NewLimiter(rate.Every(ProvisionClientBurstDuration), ProvisionClientBurstAllowance),
+			headerSync:       true,
 		},
+		retryPolicy: DefaultRetryPolicy(),
+		catalogTTL:  DefaultCatalogTTL,
 	}
 
 	for _, option := range options {
 		option(client)
 	}
 
+	client.Catalog = newCatalog(client, client.catalogTTL)
+	client.doer = client.chain(DoerFunc(client.send))
+
 	// Configure child clients
 	client.Space = SpaceClient{client}
 	client.Plan = PlanClient{client}
@@ -418,10 +465,18 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Re
 	}
 	req.Header.Set("User-Agent", c.userAgent)
 
-	if c.credentialPair.NotEmpty() {
+	var credentials CredentialPair
+	if c.credentials != nil {
+		credentials, err = c.credentials.Credentials(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving credentials: %w", err)
+		}
+	}
+
+	if credentials.NotEmpty() {
 		req.SetBasicAuth(
-			string(c.credentialPair.AccessKey),
-			string(c.credentialPair.AccessToken),
+			string(credentials.AccessKey),
+			string(credentials.AccessToken),
 		)
 
 		if _, _, ok := req.BasicAuth(); !ok {
@@ -437,12 +492,35 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Re
 	return req, nil
 }
 
-// Do performs an HTTP request against the API.
+// apiPath returns the path of req, relative to the Client's endpoint.
+func (c *Client) apiPath(req *http.Request) string {
+	endpoint, err := url.Parse(c.endpoint)
+	if err != nil {
+		return req.URL.Path
+	}
+	return "/" + strings.TrimLeft(strings.TrimPrefix(req.URL.Path, endpoint.Path), "/")
+}
+
+// Do performs an HTTP request against the API, retrying failed requests
+// per the Client's RetryPolicy. Each attempt is passed through the Client's
+// Middleware; see [WithMiddleware].
+//
+// If the Client has a Cache, GET requests for Plans, Spaces and Releases
+// may be served from it; see [WithCache].
 func (c *Client) Do(ctx context.Context, req *http.Request) (*Response, error) {
-	reqBuf := new(bytes.Buffer)
+	if c.cacheable(req) {
+		return c.doCached(ctx, req)
+	}
+	return c.do(ctx, req)
+}
 
-	// Capture the original request body
+// do performs req per Do, without consulting the Client's Cache.
+func (c *Client) do(ctx context.Context, req *http.Request) (*Response, error) {
+	var reqBody []byte
+
+	// Capture the original request body, such that it may be replayed
 	if req.ContentLength > 0 {
+		reqBuf := new(bytes.Buffer)
 		_, err := reqBuf.ReadFrom(req.Body)
 		if err != nil {
 			return nil, fmt.Errorf("error reading request body: %w", err)
@@ -452,51 +530,68 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*Response, error) {
 		if err != nil {
 			return nil, err
 		}
+		reqBody = reqBuf.Bytes()
 	}
 
-	// We only retry in the scenario of http.StatusTooManyRequests (429).
-	for {
-		respErr := &ResponseError{}
-		resp, err := c.doRequest(ctx, req, reqBuf)
-
-		switch {
-		case errors.As(err, respErr):
-			if reflect.ValueOf(respErr).IsZero() {
-				return resp, fmt.Errorf("unknown error occurred with response status %d", resp.StatusCode)
-			} else if errors.Is(err, ErrHTTPStatusTooManyRequests) {
-				// Block in this routine, if needed.
-				var delay int64
-				if delay, err = extractRetryDelay(resp); err != nil {
-					time.Sleep(time.Duration(delay) * time.Second)
-				}
-				continue
-			}
-			return resp, err
-		default:
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doRequest(ctx, req, reqBody, attempt)
+		if resp != nil {
+			resp.Attempts = attempt
+		}
+
+		if err == nil ||
+			attempt >= c.retryPolicy.maxAttempts() ||
+			!c.retryPolicy.retryable(ctx, req.Method, resp, err) {
 			return resp, err
 		}
+
+		// Block in this routine until the next attempt is due, if needed.
+		if sleepErr := sleepContext(ctx, c.retryPolicy.delay(attempt, resp)); sleepErr != nil {
+			return resp, errors.Join(
+				fmt.Errorf("failed while awaiting retry attempt %d: %w", attempt+1, sleepErr),
+				err,
+			)
+		}
 	}
 }
 
-func (c *Client) doRequest(ctx context.Context, req *http.Request, reqBuf *bytes.Buffer) (*Response, error) {
-	// Wrap the buffer in a no-op Closer, such that
+func (c *Client) doRequest(ctx context.Context, req *http.Request, reqBody []byte, attempt int) (*Response, error) {
+	// Wrap the body in a no-op Closer, such that
 	// it satisfies the ReadCloser interface
-	if req.ContentLength > 0 {
-		req.Body = io.NopCloser(reqBuf)
+	if len(reqBody) > 0 {
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
 	}
 
 	// Context canceled, timed-out, burst issue, or other rate limit issue;
 	// let the callers handle it.
-	if err := c.rateLimiter.Wait(ctx); err != nil {
+	start := time.Now()
+	if err := c.rateLimiter.wait(ctx, req.Method, c.apiPath(req)); err != nil {
 		return nil, fmt.Errorf("failed while awaiting execution per rate-limit: %w", err)
 	}
 
-	httpResp, err := c.httpClient.Do(req)
-	defer func() { err = IoClose(httpResp.Body, err) }()
+	info := operationFromContext(ctx)
+	info.Attempt, info.RateLimitWait = attempt, time.Since(start)
+	ctx = context.WithValue(ctx, requestInfoKey{}, info)
 
+	return c.doer.Do(ctx, req)
+}
+
+// send performs a single HTTP request, and decodes its response. It's the
+// innermost Doer, wrapped by the Client's middleware.
+func (c *Client) send(ctx context.Context, req *http.Request) (*Response, error) {
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("performing http request: %w", err)
+		}
+		return nil, fmt.Errorf("performing http request: %w: %w", ErrNetwork, err)
+	}
 	if httpResp == nil {
 		return nil, errors.New("received nil http.Response")
 	}
+	defer func() { err = IoClose(httpResp.Body, err) }()
+
+	c.rateLimiter.observe(httpResp.Header)
 
 	resp, err := NewResponse()
 	if err != nil {
@@ -515,19 +610,17 @@ func (c *Client) doRequest(ctx context.Context, req *http.Request, reqBuf *bytes
 	// response, it would be jarring to receive a message about an internal
 	// unmarshaling attempt, rather than to receive the HTTP Status Error
 	if resp.StatusCode >= http.StatusBadRequest {
-		respErr := ResponseError{Status: resp.StatusCode}
-
-		if ok := resp.isJSON(); ok {
-			// Suppress unmarshaling errors in the event that the response didn't
-			// contain a message.
-			_ = json.Unmarshal(resp.BodyBuf.Bytes(), &respErr)
+		// Attempts is recorded here, before middleware may wrap the error.
+		apiErr := newAPIError(req, resp)
+		if info, ok := RequestInfoFromContext(ctx); ok {
+			apiErr.Attempts = info.Attempt
 		}
-
-		return resp, respErr
+		return resp, apiErr
 	}
 
-	// Extract the pagination details
-	if resp.isJSON() {
+	// Extract the pagination details; responses such as 304 Not Modified
+	// may have no body.
+	if resp.isJSON() && resp.BodyBuf.Len() > 0 {
 		err = json.Unmarshal(resp.BodyBuf.Bytes(), &resp)
 		if err != nil {
 			return resp, fmt.Errorf("error unmarshaling response body for pagination: %w", err)
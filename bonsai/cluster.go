@@ -10,6 +10,9 @@ import (
 	"net/url"
 	"path"
 	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -22,7 +25,8 @@ const (
 //
 // This attribute should not be used for real-time monitoring!
 // Stats are updated every 10-15 minutes. To monitor real-time metrics, monitor
-// your cluster directly, via the Index Stats API.
+// your cluster directly, via the Index Stats API; the search package makes
+// such requests.
 type ClusterStats struct {
 	// Number of documents in the index.
 	Docs int64 `json:"docs,omitempty"`
@@ -136,28 +140,38 @@ type ClustersResultDestroy struct {
 	Monitor string `json:"monitor"`
 }
 
+// ClusterSlugFromURI returns the slug of the Cluster identified by uri, such
+// as the Cluster.URI or ClustersResultCreate.Monitor
+// "https://api.bonsai.io/clusters/my-cluster-1234567890". It returns an
+// empty string if uri doesn't identify a Cluster.
+func ClusterSlugFromURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+
+	_, rest, ok := strings.Cut(u.Path, ClusterAPIBasePath+"/")
+	if !ok {
+		return ""
+	}
+	slug, _, _ := strings.Cut(rest, "/")
+	return slug
+}
+
 // ClusterClient is a client for the Clusters API.
 type ClusterClient struct {
 	*Client
 }
 
-// Do performs an HTTP request against the API, with any required
-// Cluster-specific configuration/limitations - for example, rate limiting.
-func (c *ClusterClient) Do(ctx context.Context, req *http.Request) (*Response, error) {
-	// Allow non-provisioning Cluster endpoint requests to continue
-	if req.Method != http.MethodPost {
-		return c.Client.Do(ctx, req)
-	}
+// Valid values for [ClusterAllOpts.Tenancy].
+const (
+	ClusterTenancyParent = "parent"
+	ClusterTenancyChild  = "child"
+)
 
-	// Limit provision requests
-	err := c.rateLimiter.provisionLimiter.Wait(ctx)
-	if err != nil {
-		// Context canceled, timed-out, burst issue, or other rate limit issue;
-		// let the callers handle it.
-		return nil, fmt.Errorf("failed while awaiting execution per rate-limit: %w", err)
-	}
-	return c.Client.Do(ctx, req)
-}
+// ErrInvalidClusterTenancy is returned, wrapped in an [OptionError], when
+// [ClusterAllOpts.Tenancy] holds an unsupported value.
+var ErrInvalidClusterTenancy = errors.New(`tenancy must be one of "parent" or "child"`)
 
 type ClusterAllOpts struct {
 	// Optional. A query string for filtering matching clusters.
@@ -173,6 +187,15 @@ type ClusterAllOpts struct {
 	Location string `url:"location,omitempty"`
 }
 
+func (o ClusterAllOpts) Valid() error {
+	switch o.Tenancy {
+	case "", ClusterTenancyParent, ClusterTenancyChild:
+		return nil
+	default:
+		return OptionError{Field: "tenancy", Value: o.Tenancy, Err: ErrInvalidClusterTenancy}
+	}
+}
+
 type ClusterCreateOpts struct {
 	// Required. A String representing the name for the new cluster.
 	Name string `json:"name"`
@@ -231,8 +254,6 @@ func (o clusterListOpts) values() (url.Values, error) {
 
 // list returns a list of Clusters for the page specified,
 // by performing a GET request against [spaceAPIBasePath].
-//
-// Note: Pagination is not currently supported.
 func (c *ClusterClient) list(ctx context.Context, opt clusterListOpts) (
 	[]Cluster,
 	*Response,
@@ -249,6 +270,10 @@ func (c *ClusterClient) list(ctx context.Context, opt clusterListOpts) (
 		Clusters: make([]Cluster, 0, defaultResponseCapacity),
 	}
 
+	if err = opt.ClusterAllOpts.Valid(); err != nil {
+		return results.Clusters, nil, fmt.Errorf("invalid list options (%+v): %w", opt.ClusterAllOpts, err)
+	}
+
 	reqURL, err = url.Parse(ClusterAPIBasePath)
 	if err != nil {
 		return results.Clusters, nil, fmt.Errorf("cannot parse relative url from basepath (%s): %w", ClusterAPIBasePath, err)
@@ -271,7 +296,7 @@ func (c *ClusterClient) list(ctx context.Context, opt clusterListOpts) (
 		return results.Clusters, nil, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Cluster.List"}), req)
 	if err != nil {
 		return results.Clusters, resp, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -283,36 +308,33 @@ func (c *ClusterClient) list(ctx context.Context, opt clusterListOpts) (
 	return results.Clusters, resp, nil
 }
 
-// All lists all active clusters on your account.
-func (c *ClusterClient) All(ctx context.Context) ([]Cluster, error) {
-	var (
-		err  error
-		resp *Response
-	)
-
-	allResults := make([]Cluster, 0, defaultListResultSize)
-	// No pagination support as yet, but support it for future use
-
-	err = c.all(ctx, newEmptyListOpts(), func(opt listOpts) (*Response, error) {
-		var listResults []Cluster
+// List lists a single page, specified by page, of the clusters on your
+// account matching opt. The pagination details reported by the API are
+// returned alongside the clusters.
+func (c *ClusterClient) List(ctx context.Context, opt ClusterAllOpts, page PageOpts) (
+	[]Cluster,
+	PaginatedResponse,
+	error,
+) {
+	clusters, resp, err := c.list(ctx, clusterListOpts{listOpts: page.listOpts(), ClusterAllOpts: opt})
+	if err != nil {
+		return clusters, PaginatedResponse{}, fmt.Errorf("client.list failed: %w", err)
+	}
 
-		listResults, resp, err = c.list(ctx, clusterListOpts{listOpts: opt})
-		if err != nil {
-			return resp, fmt.Errorf("client.list failed: %w", err)
-		}
+	return clusters, resp.PaginatedResponse, nil
+}
 
-		allResults = append(allResults, listResults...)
-		if len(allResults) >= resp.TotalRecords {
-			resp.MarkPaginationComplete()
-		}
-		return resp, err
+// Iter returns a Pager over the clusters on your account matching opt,
+// starting from the page specified by page.
+func (c *ClusterClient) Iter(opt ClusterAllOpts, page PageOpts) *Pager[Cluster] {
+	return newPager(c.Client, page, func(ctx context.Context, o listOpts) ([]Cluster, *Response, error) {
+		return c.list(ctx, clusterListOpts{listOpts: o, ClusterAllOpts: opt})
 	})
+}
 
-	if err != nil {
-		return allResults, fmt.Errorf("client.all failed: %w", err)
-	}
-
-	return allResults, err
+// All lists all active clusters on your account.
+func (c *ClusterClient) All(ctx context.Context) ([]Cluster, error) {
+	return c.Iter(ClusterAllOpts{}, PageOpts{}).All(ctx)
 }
 
 // GetBySlug gets a Cluster from the Clusters API by its slug.
@@ -338,7 +360,7 @@ func (c *ClusterClient) GetBySlug(ctx context.Context, slug string) (Cluster, er
 		return result, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Cluster.GetBySlug", Cluster: slug}), req)
 	if err != nil {
 		return result, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -356,6 +378,15 @@ func (c *ClusterClient) GetBySlug(ctx context.Context, slug string) (Cluster, er
 func (c *ClusterClient) Create(ctx context.Context, opt ClusterCreateOpts) (
 	ClustersResultCreate,
 	error,
+) {
+	return c.create(ctx, opt, "")
+}
+
+// create performs Create, sending idempotencyKey, if not empty, in the
+// Idempotency-Key header.
+func (c *ClusterClient) create(ctx context.Context, opt ClusterCreateOpts, idempotencyKey string) (
+	ClustersResultCreate,
+	error,
 ) {
 	var (
 		req     *http.Request
@@ -376,6 +407,12 @@ func (c *ClusterClient) Create(ctx context.Context, opt ClusterCreateOpts) (
 		return result, fmt.Errorf("invalid create options (%v): %w", opt, err)
 	}
 
+	if c.validateCreate {
+		if err = c.Catalog.ValidateCreate(ctx, opt); err != nil {
+			return result, fmt.Errorf("invalid create options (%v): %w", opt, err)
+		}
+	}
+
 	reqBody, err = json.Marshal(opt)
 	if err != nil {
 		return result, fmt.Errorf("failed to marshal options (%v): %w", opt, err)
@@ -385,8 +422,11 @@ func (c *ClusterClient) Create(ctx context.Context, opt ClusterCreateOpts) (
 	if err != nil {
 		return result, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
+	if idempotencyKey != "" {
+		req.Header.Set(HeaderIdempotencyKey, idempotencyKey)
+	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Cluster.Create", Plan: opt.Plan}), req)
 	if err != nil {
 		return result, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -395,6 +435,10 @@ func (c *ClusterClient) Create(ctx context.Context, opt ClusterCreateOpts) (
 		return result, fmt.Errorf("json.Unmarshal failed: %w", err)
 	}
 
+	if err = c.storeCredentials(ctx, opt, result); err != nil {
+		return result, err
+	}
+
 	return result, nil
 }
 
@@ -435,7 +479,7 @@ func (c *ClusterClient) Update(ctx context.Context, slug string, opt ClusterUpda
 		return result, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Cluster.Update", Cluster: slug, Plan: opt.Plan}), req)
 	if err != nil {
 		return result, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -471,7 +515,7 @@ func (c *ClusterClient) Destroy(ctx context.Context, slug string) (ClustersResul
 		return result, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Cluster.Destroy", Cluster: slug}), req)
 	if err != nil {
 		return result, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -482,3 +526,175 @@ func (c *ClusterClient) Destroy(ctx context.Context, slug string) (ClustersResul
 
 	return result, nil
 }
+
+// Cluster state waiter defaults.
+const (
+	// DefaultClusterWaitPollInterval is the initial delay between cluster
+	// state checks performed by [ClusterClient.WaitForState].
+	DefaultClusterWaitPollInterval = 5 * time.Second
+	// DefaultClusterWaitMaxPollInterval caps the delay between cluster state
+	// checks, once backoff has been applied.
+	DefaultClusterWaitMaxPollInterval = 1 * time.Minute
+	// DefaultClusterWaitBackoffMultiplier is the factor by which the poll
+	// interval grows after each check.
+	DefaultClusterWaitBackoffMultiplier = 1.5
+)
+
+// ErrClusterUnexpectedState is returned (wrapped in a [ClusterStateError])
+// when a cluster being waited upon reaches a failure state.
+var ErrClusterUnexpectedState = errors.New("cluster reached an unexpected state")
+
+// ClusterStateError reports that a cluster reached a state that the caller
+// considered a failure while waiting for it to reach one of Targets.
+type ClusterStateError struct {
+	// Slug of the cluster being waited upon.
+	Slug string
+	// State is the failure state that the cluster reached.
+	State ClusterState
+	// Targets are the states that were being waited for.
+	Targets []ClusterState
+}
+
+func (e ClusterStateError) Error() string {
+	return fmt.Sprintf("cluster %s reached state %q while waiting for %v", e.Slug, e.State, e.Targets)
+}
+
+func (e ClusterStateError) Is(target error) bool {
+	return target == ErrClusterUnexpectedState
+}
+
+// DefaultClusterWaitFailures returns the states considered failures by
+// [ClusterClient.WaitForState] when [ClusterWaitOpts.Failures] is empty.
+// ClusterStateDeprovisioning isn't among them, such that a destroyed
+// Cluster can be waited on until it's deprovisioned.
+func DefaultClusterWaitFailures() []ClusterState {
+	return []ClusterState{
+		ClusterStateDisabled,
+		ClusterStateDeprovisioned,
+	}
+}
+
+// ClusterWaitOpts configures [ClusterClient.WaitForState].
+type ClusterWaitOpts struct {
+	// Required. The states which will end the wait successfully.
+	Targets []ClusterState
+	// Optional. The states which will end the wait with a ClusterStateError.
+	// A state listed in Targets always takes precedence over Failures.
+	// Default: [DefaultClusterWaitFailures].
+	Failures []ClusterState
+	// Optional. The initial delay between state checks.
+	// Default: DefaultClusterWaitPollInterval.
+	PollInterval time.Duration
+	// Optional. The maximum delay between state checks.
+	// Default: DefaultClusterWaitMaxPollInterval.
+	MaxPollInterval time.Duration
+	// Optional. The factor by which the delay between state checks grows.
+	// Values below 1 are treated as 1, disabling backoff.
+	// Default: DefaultClusterWaitBackoffMultiplier.
+	BackoffMultiplier float64
+	// Optional. An upper bound on the total time spent waiting, applied on
+	// top of any deadline already carried by the context.
+	Timeout time.Duration
+}
+
+func (o ClusterWaitOpts) Valid() error {
+	if len(o.Targets) == 0 {
+		return errors.New("targets can't be empty")
+	}
+	if o.PollInterval < 0 || o.MaxPollInterval < 0 || o.Timeout < 0 {
+		return errors.New("durations can't be negative")
+	}
+	return nil
+}
+
+// withDefaults fills in any unset optional values.
+func (o ClusterWaitOpts) withDefaults() ClusterWaitOpts {
+	if len(o.Failures) == 0 {
+		o.Failures = DefaultClusterWaitFailures()
+	}
+	if o.PollInterval == 0 {
+		o.PollInterval = DefaultClusterWaitPollInterval
+	}
+	if o.MaxPollInterval == 0 {
+		o.MaxPollInterval = max(DefaultClusterWaitMaxPollInterval, o.PollInterval)
+	}
+	if o.BackoffMultiplier == 0 {
+		o.BackoffMultiplier = DefaultClusterWaitBackoffMultiplier
+	}
+	o.BackoffMultiplier = max(o.BackoffMultiplier, 1)
+	return o
+}
+
+// WaitForState polls the cluster associated with the slug until it reaches
+// one of the target states, reaches one of the failure states, or the
+// context is done.
+//
+// The most recently retrieved Cluster is always returned. When a failure
+// state is reached, the error is a [ClusterStateError]. If the targets
+// include [ClusterStateDeprovisioned], a cluster that can no longer be
+// found is considered to have reached that state.
+//
+// Polls which fail with a network or server error are retried until the
+// context is done, in which case the error of the last such poll is
+// returned along with that of the context. Any other error ends the wait.
+func (c *ClusterClient) WaitForState(ctx context.Context, slug string, opt ClusterWaitOpts) (Cluster, error) {
+	var (
+		cluster Cluster
+		// pollErr holds the error of the last poll, if it failed transiently.
+		pollErr error
+	)
+
+	if err := opt.Valid(); err != nil {
+		return cluster, fmt.Errorf("invalid wait options (%+v): %w", opt, err)
+	}
+	opt = opt.withDefaults()
+
+	if opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opt.Timeout)
+		defer cancel()
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	delay := opt.PollInterval
+	for {
+		select {
+		case <-ctx.Done():
+			if pollErr != nil {
+				return cluster, fmt.Errorf("waiting for cluster %s to reach %v: %w (last poll: %w)",
+					slug, opt.Targets, ctx.Err(), pollErr)
+			}
+			return cluster, fmt.Errorf("waiting for cluster %s to reach %v: %w", slug, opt.Targets, ctx.Err())
+		case <-timer.C:
+		}
+
+		// The most recently retrieved Cluster is kept should a poll fail.
+		polled, err := c.GetBySlug(ctx, slug)
+		switch {
+		case errors.Is(err, ErrHTTPStatusNotFound) && slices.Contains(opt.Targets, ClusterStateDeprovisioned):
+			cluster.Slug = slug
+			cluster.State = ClusterStateDeprovisioned
+			return cluster, nil
+		case err != nil && ctx.Err() != nil:
+			// The poll was cut short as the context is done, which is
+			// reported in place of its error.
+		case errors.Is(err, ErrNetwork) || errors.Is(err, ErrHTTPStatusServerError):
+			pollErr = err
+		case err != nil:
+			return cluster, fmt.Errorf("waiting for cluster %s to reach %v: %w", slug, opt.Targets, err)
+		default:
+			cluster, pollErr = polled, nil
+			switch {
+			case slices.Contains(opt.Targets, cluster.State):
+				return cluster, nil
+			case slices.Contains(opt.Failures, cluster.State):
+				return cluster, ClusterStateError{Slug: slug, State: cluster.State, Targets: opt.Targets}
+			}
+		}
+
+		timer.Reset(delay)
+		delay = min(time.Duration(float64(delay)*opt.BackoffMultiplier), opt.MaxPollInterval)
+	}
+}
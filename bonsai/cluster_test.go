@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/omc/bonsai-api-go/v2/bonsai"
 )
@@ -357,12 +358,10 @@ func (s *ClientMockTestSuite) TestClusterClient_Create() {
 			}
 		`
 
-		resp := &bonsai.ClustersResultCreate{}
-		err := json.Unmarshal([]byte(respStr), resp)
-		s.NoError(err, "unmarshals json into bonsai.ClustersResultCreate")
-
-		err = json.NewEncoder(w).Encode(resp)
-		s.NoError(err, "encodes bonsai.ClustersResultCreate into json on the writer")
+		// Marshaling a ClusterAccess redacts its credentials, so the
+		// response is written as is.
+		_, err := w.Write([]byte(respStr))
+		s.NoError(err, "writes response")
 	})
 
 	expect := bonsai.ClustersResultCreate{
@@ -460,6 +459,290 @@ func (s *ClientMockTestSuite) TestClusterClient_Delete() {
 	s.Equal(expect, resultResp, "items in expect match items in received cluster update response")
 }
 
+func (s *ClientMockTestSuite) TestClusterClient_List() {
+	const listPath = "/list-filtered"
+
+	// The suite's shared client may only have one handler for the clusters
+	// endpoint, so serve this test from a client with a distinct endpoint.
+	client := bonsai.NewClient(bonsai.WithEndpoint(s.server.URL + listPath))
+
+	var received url.Values
+	s.serveMux.Get(listPath+bonsai.ClusterAPIBasePath, func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		_, err := fmt.Fprint(w, `
+			{
+				"pagination": {
+					"page_number": 2,
+					"page_size": 1,
+					"total_records": 3
+				},
+				"clusters": [
+					{
+						"slug": "second-testing-clust-1234567890",
+						"name": "second_testing_cluster",
+						"space": {
+							"path": "omc/bonsai/us-east-1/common"
+						},
+						"state": "PROVISIONED"
+					}
+				]
+			}
+		`)
+		s.NoError(err, "wrote response string to writer")
+	})
+
+	clusters, pagination, err := client.Cluster.List(
+		context.Background(),
+		bonsai.ClusterAllOpts{
+			Query:    "testing",
+			Tenancy:  bonsai.ClusterTenancyParent,
+			Location: "omc/bonsai/us-east-1",
+		},
+		bonsai.PageOpts{Page: 2, Size: 1},
+	)
+	s.NoError(err, "successfully list clusters")
+
+	s.Equal(url.Values{
+		"q":        []string{"testing"},
+		"tenancy":  []string{"parent"},
+		"location": []string{"omc/bonsai/us-east-1"},
+		"page":     []string{"2"},
+		"size":     []string{"1"},
+	}, received, "filters and pagination are sent as query parameters")
+
+	s.Len(clusters, 1)
+	s.Equal("second-testing-clust-1234567890", clusters[0].Slug)
+	s.Equal(bonsai.PaginatedResponse{PageNumber: 2, PageSize: 1, TotalRecords: 3}, pagination)
+
+	s.Run("invalid tenancy is rejected client-side", func() {
+		received = nil
+
+		_, _, err = client.Cluster.List(
+			context.Background(),
+			bonsai.ClusterAllOpts{Tenancy: "sibling"},
+			bonsai.PageOpts{},
+		)
+		s.ErrorIs(err, bonsai.ErrInvalidOption)
+		s.ErrorIs(err, bonsai.ErrInvalidClusterTenancy)
+
+		optErr := bonsai.OptionError{}
+		s.ErrorAs(err, &optErr)
+		s.Equal("tenancy", optErr.Field)
+		s.Equal("sibling", optErr.Value)
+		s.Nil(received, "no request was made")
+	})
+}
+
+func (s *ClientMockTestSuite) TestClusterSlugFromURI() {
+	testCases := []struct {
+		uri    string
+		expect string
+	}{
+		{uri: "https://api.bonsai.io/clusters/my-cluster-1234567890", expect: "my-cluster-1234567890"},
+		{uri: "https://api.bonsai.io/clusters/my-cluster-1234567890/", expect: "my-cluster-1234567890"},
+		{uri: "http://127.0.0.1:8080/prefix/clusters/my-cluster-1234567890", expect: "my-cluster-1234567890"},
+		{uri: "https://api.bonsai.io/plans/sandbox-aws-us-east-1", expect: ""},
+		{uri: "https://api.bonsai.io/clusters", expect: ""},
+		{uri: "://not a uri", expect: ""},
+		{uri: "", expect: ""},
+	}
+
+	for _, tc := range testCases {
+		s.Equal(tc.expect, bonsai.ClusterSlugFromURI(tc.uri), tc.uri)
+	}
+}
+
+func (s *ClientMockTestSuite) TestClusterClient_WaitForState() {
+	const clusterRespFmt = `
+		{
+			"cluster": {
+				"slug": "%s",
+				"name": "waiting_cluster",
+				"state": "%s"
+			}
+		}
+	`
+
+	serveStates := func(slug string, states ...bonsai.ClusterState) *int {
+		calls := 0
+		urlPath, err := url.JoinPath(bonsai.ClusterAPIBasePath, slug)
+		s.NoError(err, "successfully resolved path")
+
+		s.serveMux.Get(urlPath, func(w http.ResponseWriter, _ *http.Request) {
+			state := states[min(calls, len(states)-1)]
+			calls++
+
+			w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+			_, err = fmt.Fprintf(w, clusterRespFmt, slug, state)
+			s.NoError(err, "wrote response string to writer")
+		})
+		return &calls
+	}
+
+	opts := bonsai.ClusterWaitOpts{
+		Targets:      []bonsai.ClusterState{bonsai.ClusterStateProvisioned},
+		PollInterval: time.Millisecond,
+	}
+
+	s.Run("reaches target state", func() {
+		const slug = "wait-provisioned-1234567890"
+		calls := serveStates(
+			slug,
+			bonsai.ClusterStateProvisioning,
+			bonsai.ClusterStateProvisioning,
+			bonsai.ClusterStateProvisioned,
+		)
+
+		cluster, err := s.client.Cluster.WaitForState(context.Background(), slug, opts)
+		s.NoError(err, "wait for provisioned state")
+		s.Equal(bonsai.ClusterStateProvisioned, cluster.State)
+		s.Equal(slug, cluster.Slug)
+		s.Equal(3, *calls, "polled until the target state was reached")
+	})
+
+	s.Run("reaches failure state", func() {
+		const slug = "wait-disabled-1234567890"
+		serveStates(slug, bonsai.ClusterStateProvisioning, bonsai.ClusterStateDisabled)
+
+		cluster, err := s.client.Cluster.WaitForState(context.Background(), slug, opts)
+		s.ErrorIs(err, bonsai.ErrClusterUnexpectedState)
+
+		stateErr := bonsai.ClusterStateError{}
+		s.ErrorAs(err, &stateErr)
+		s.Equal(bonsai.ClusterStateDisabled, stateErr.State)
+		s.Equal(bonsai.ClusterStateDisabled, cluster.State)
+	})
+
+	s.Run("context deadline exceeded", func() {
+		const slug = "wait-timeout-1234567890"
+		serveStates(slug, bonsai.ClusterStateUpdatingPlan)
+
+		timeoutOpts := opts
+		timeoutOpts.Timeout = 20 * time.Millisecond
+
+		cluster, err := s.client.Cluster.WaitForState(context.Background(), slug, timeoutOpts)
+		s.ErrorIs(err, context.DeadlineExceeded)
+		s.Equal(bonsai.ClusterStateUpdatingPlan, cluster.State, "last seen cluster is returned")
+	})
+
+	s.Run("poll fails", func() {
+		const slug = "wait-failing-1234567890"
+		urlPath, err := url.JoinPath(bonsai.ClusterAPIBasePath, slug)
+		s.NoError(err, "successfully resolved path")
+
+		calls := 0
+		s.serveMux.Get(urlPath, func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+			if calls > 1 {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"errors": ["Forbidden."], "status": 403}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, clusterRespFmt, slug, bonsai.ClusterStateProvisioning)
+		})
+
+		cluster, err := s.client.Cluster.WaitForState(context.Background(), slug, opts)
+		s.ErrorIs(err, bonsai.ErrHTTPStatusForbidden)
+		s.Equal(bonsai.ClusterStateProvisioning, cluster.State, "last retrieved cluster is returned")
+		s.Equal("waiting_cluster", cluster.Name, "last retrieved cluster is returned")
+	})
+
+	// serveFailures serves the states, with a server error in place of each
+	// empty state.
+	serveFailures := func(slug string, states ...bonsai.ClusterState) *int {
+		urlPath, err := url.JoinPath(bonsai.ClusterAPIBasePath, slug)
+		s.NoError(err, "successfully resolved path")
+
+		calls := 0
+		s.serveMux.Get(urlPath, func(w http.ResponseWriter, _ *http.Request) {
+			state := states[min(calls, len(states)-1)]
+			calls++
+
+			w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+			if state == "" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"errors": ["Service Unavailable."], "status": 503}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, clusterRespFmt, slug, state)
+		})
+		return &calls
+	}
+
+	s.Run("transient poll failures are retried", func() {
+		const slug = "wait-transient-1234567890"
+		calls := serveFailures(slug, bonsai.ClusterStateProvisioning, "", "", bonsai.ClusterStateProvisioned)
+
+		cluster, err := s.client.Cluster.WaitForState(context.Background(), slug, opts)
+		s.NoError(err, "server errors don't end the wait")
+		s.Equal(bonsai.ClusterStateProvisioned, cluster.State)
+		s.Equal(4, *calls)
+	})
+
+	s.Run("transient poll failures until the deadline", func() {
+		const slug = "wait-unavailable-1234567890"
+		serveFailures(slug, bonsai.ClusterStateProvisioning, "")
+
+		timeoutOpts := opts
+		timeoutOpts.Timeout = 20 * time.Millisecond
+
+		cluster, err := s.client.Cluster.WaitForState(context.Background(), slug, timeoutOpts)
+		s.ErrorIs(err, context.DeadlineExceeded)
+		s.ErrorIs(err, bonsai.ErrHTTPStatusServerError, "the last poll's error is returned")
+		s.Equal(bonsai.ClusterStateProvisioning, cluster.State, "last retrieved cluster is returned")
+	})
+
+	s.Run("network errors until the deadline", func() {
+		client := bonsai.NewClient(bonsai.WithEndpoint("http://127.0.0.1:1"))
+
+		timeoutOpts := opts
+		timeoutOpts.Timeout = 20 * time.Millisecond
+
+		_, err := client.Cluster.WaitForState(context.Background(), "wait-unreachable-1234567890", timeoutOpts)
+		s.ErrorIs(err, context.DeadlineExceeded)
+		s.ErrorIs(err, bonsai.ErrNetwork, "the last poll's error is returned")
+	})
+
+	s.Run("deprovisioned cluster is no longer found", func() {
+		const slug = "wait-gone-1234567890"
+
+		deprovisionedOpts := opts
+		deprovisionedOpts.Targets = []bonsai.ClusterState{bonsai.ClusterStateDeprovisioned}
+
+		cluster, err := s.client.Cluster.WaitForState(context.Background(), slug, deprovisionedOpts)
+		s.NoError(err, "not found is treated as deprovisioned")
+		s.Equal(bonsai.ClusterStateDeprovisioned, cluster.State)
+	})
+
+	s.Run("deprovisioning cluster reaches deprovisioned", func() {
+		const slug = "wait-deprovisioning-1234567890"
+		calls := serveStates(slug, bonsai.ClusterStateDeprovisioning, bonsai.ClusterStateDeprovisioned)
+
+		deprovisionedOpts := opts
+		deprovisionedOpts.Targets = []bonsai.ClusterState{bonsai.ClusterStateDeprovisioned}
+
+		cluster, err := s.client.Cluster.WaitForState(context.Background(), slug, deprovisionedOpts)
+		s.NoError(err, "deprovisioning isn't a failure by default")
+		s.Equal(bonsai.ClusterStateDeprovisioned, cluster.State)
+		s.Equal(2, *calls)
+	})
+
+	s.Run("default failures", func() {
+		s.Equal(
+			[]bonsai.ClusterState{bonsai.ClusterStateDisabled, bonsai.ClusterStateDeprovisioned},
+			bonsai.DefaultClusterWaitFailures(),
+		)
+	})
+
+	s.Run("invalid options", func() {
+		_, err := s.client.Cluster.WaitForState(context.Background(), "any-1234567890", bonsai.ClusterWaitOpts{})
+		s.Error(err, "targets are required")
+	})
+}
+
 // VCR Tests.
 func (s *ClientVCRTestSuite) TestClusterClient_All() {
 	ctx := context.Background()
@@ -0,0 +1,269 @@
+// Package collector exports the statistics of the clusters on a Bonsai
+// account as Prometheus metrics.
+//
+// Cluster statistics are only updated by the API every 10-15 minutes, so
+// the Collector caches the clusters listed by [bonsai.ClusterClient.All]
+// between scrapes, rather than spending rate-limited requests on each one.
+// Scrapes never wait on the API: they export the cached clusters, while
+// stale clusters are listed again in the background.
+//
+//	c := collector.New(client)
+//	prometheus.MustRegister(c)
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// Collector defaults.
+const (
+	// DefaultRefreshInterval is the default maximum age of the cached
+	// clusters, after which they're listed again on the next scrape.
+	DefaultRefreshInterval = 5 * time.Minute
+	// DefaultRefreshTimeout is the default time allowed to list clusters
+	// in the background.
+	DefaultRefreshTimeout = 30 * time.Second
+	// Namespace prefixes the names of all exported metrics.
+	Namespace = "bonsai"
+)
+
+// clusterLabels label each of the per-cluster metrics.
+func clusterLabels() []string {
+	return []string{"slug", "plan", "space", "release", "state"}
+}
+
+// clusterStates returns all known cluster states, each of which is exported,
+// even when no clusters are in that state.
+func clusterStates() []bonsai.ClusterState {
+	return []bonsai.ClusterState{
+		bonsai.ClusterStateDeprovisioned,
+		bonsai.ClusterStateDeprovisioning,
+		bonsai.ClusterStateDisabled,
+		bonsai.ClusterStateMaintenance,
+		bonsai.ClusterStateProvisioned,
+		bonsai.ClusterStateProvisioning,
+		bonsai.ClusterStateReadOnly,
+		bonsai.ClusterStateUpdatingPlan,
+	}
+}
+
+// Option configures a Collector.
+type Option func(*Collector)
+
+// WithRefreshInterval configures the maximum age of the cached clusters,
+// after which they're listed again, in the background, on the next scrape.
+// Failed attempts are also retried no more often than this.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(c *Collector) {
+		c.refreshInterval = d
+	}
+}
+
+// WithRefreshTimeout configures the time allowed to list clusters in the
+// background.
+func WithRefreshTimeout(d time.Duration) Option {
+	return func(c *Collector) {
+		c.refreshTimeout = d
+	}
+}
+
+// Collector is a prometheus.Collector exporting the statistics and states
+// of all clusters on an account. It's safe for concurrent use.
+type Collector struct {
+	client          *bonsai.Client
+	refreshInterval time.Duration
+	refreshTimeout  time.Duration
+	now             func() time.Time
+
+	docs          *prometheus.Desc
+	shardsUsed    *prometheus.Desc
+	dataBytesUsed *prometheus.Desc
+	clusters      *prometheus.Desc
+	refreshOK     *prometheus.Desc
+	refreshedAt   *prometheus.Desc
+
+	// refreshMu serializes refreshes, such that mu is only held to read
+	// or replace the cached clusters, and never during requests.
+	refreshMu sync.Mutex
+
+	mu          sync.Mutex
+	cached      []bonsai.Cluster
+	lastRefresh time.Time
+	lastAttempt time.Time
+	lastErr     error
+	refreshing  bool
+}
+
+// New returns a Collector which lists clusters with client.
+func New(client *bonsai.Client, opts ...Option) *Collector {
+	c := &Collector{
+		client:          client,
+		refreshInterval: DefaultRefreshInterval,
+		refreshTimeout:  DefaultRefreshTimeout,
+		now:             time.Now,
+
+		docs: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "cluster", "docs"),
+			"Number of documents in the cluster's indices.",
+			clusterLabels(), nil,
+		),
+		shardsUsed: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "cluster", "shards_used"),
+			"Number of shards the cluster is using.",
+			clusterLabels(), nil,
+		),
+		dataBytesUsed: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "cluster", "data_bytes_used"),
+			"Number of bytes the cluster is using on-disk.",
+			clusterLabels(), nil,
+		),
+		clusters: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "clusters"),
+			"Number of clusters on the account, by state.",
+			[]string{"state"}, nil,
+		),
+		refreshOK: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "collector", "last_refresh_success"),
+			"Whether the most recent attempt to list clusters succeeded.",
+			nil, nil,
+		),
+		refreshedAt: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "collector", "last_refresh_timestamp_seconds"),
+			"Unix time at which clusters were last listed successfully.",
+			nil, nil,
+		),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.docs
+	ch <- c.shardsUsed
+	ch <- c.dataBytesUsed
+	ch <- c.clusters
+	ch <- c.refreshOK
+	ch <- c.refreshedAt
+}
+
+// Collect implements prometheus.Collector, exporting the cached clusters.
+// If the most recent attempt to list clusters is older than the refresh
+// interval, clusters are listed again in the background, for later scrapes;
+// Collect never waits on the API.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	cached, lastRefresh, lastErr := c.snapshot()
+
+	refreshOK := 1.0
+	if lastErr != nil || lastRefresh.IsZero() {
+		refreshOK = 0
+	}
+	ch <- prometheus.MustNewConstMetric(c.refreshOK, prometheus.GaugeValue, refreshOK)
+
+	if lastRefresh.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.refreshedAt, prometheus.GaugeValue, float64(lastRefresh.Unix()))
+
+	counts := make(map[bonsai.ClusterState]int, len(clusterStates()))
+	for _, state := range clusterStates() {
+		counts[state] = 0
+	}
+
+	for _, cluster := range cached {
+		counts[cluster.State]++
+
+		labels := []string{
+			cluster.Slug,
+			cluster.Plan.Slug,
+			cluster.Space.Path,
+			cluster.Release.Slug,
+			string(cluster.State),
+		}
+		ch <- prometheus.MustNewConstMetric(c.docs, prometheus.GaugeValue, float64(cluster.Stats.Docs), labels...)
+		ch <- prometheus.MustNewConstMetric(c.shardsUsed, prometheus.GaugeValue, float64(cluster.Stats.ShardsUsed), labels...)
+		ch <- prometheus.MustNewConstMetric(
+			c.dataBytesUsed, prometheus.GaugeValue, float64(cluster.Stats.DataBytesUsed), labels...,
+		)
+	}
+
+	for state, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.clusters, prometheus.GaugeValue, float64(count), string(state))
+	}
+}
+
+// Refresh lists the clusters on the account, replacing those cached.
+// Concurrent calls are serialized, but don't block scrapes or LastRefresh.
+func (c *Collector) Refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	c.mu.Lock()
+	c.lastAttempt = c.now()
+	c.mu.Unlock()
+
+	clusters, err := c.client.Cluster.All(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastErr = err
+	if err != nil {
+		return err
+	}
+
+	c.cached, c.lastRefresh = clusters, c.now()
+	return nil
+}
+
+// LastRefresh returns the time at which clusters were last listed
+// successfully, and the error, if any, from the most recent attempt. It
+// doesn't wait for a refresh underway.
+func (c *Collector) LastRefresh() (time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lastRefresh, c.lastErr
+}
+
+// snapshot returns the cached clusters, which are never modified in place,
+// along with the outcome of the most recent refresh. If the most recent
+// attempt is older than the refresh interval, and no refresh is underway,
+// one is started in the background.
+func (c *Collector) snapshot() ([]bonsai.Cluster, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.refreshing && (c.lastAttempt.IsZero() || c.now().Sub(c.lastAttempt) >= c.refreshInterval) {
+		c.refreshing = true
+		go c.backgroundRefresh()
+	}
+
+	return c.cached, c.lastRefresh, c.lastErr
+}
+
+// backgroundRefresh refreshes the cached clusters on behalf of a scrape.
+func (c *Collector) backgroundRefresh() {
+	defer func() {
+		c.mu.Lock()
+		c.refreshing = false
+		c.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.refreshTimeout)
+	defer cancel()
+
+	// Failures are exported as last_refresh_success, and stale clusters
+	// continue to be exported.
+	_ = c.Refresh(ctx)
+}
@@ -0,0 +1,195 @@
+package collector_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/bonsaitest"
+	"github.com/omc/bonsai-api-go/v2/bonsai/collector"
+)
+
+type CollectorTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all HTTP request tests
+	suite.Suite
+
+	// server is the fake API server, recreated for each test
+	server *bonsaitest.Server
+	// client is wired to make requests against server
+	client *bonsai.Client
+}
+
+func (s *CollectorTestSuite) SetupTest() {
+	fixtures := bonsaitest.DefaultFixtures()
+	fixtures.Clusters = []bonsai.Cluster{
+		{
+			Slug:    "search-1234567890",
+			Name:    "search",
+			Plan:    bonsai.Plan{Slug: "standard-sm"},
+			Space:   bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+			Release: bonsai.Release{Slug: "opensearch-2.6.0-mt"},
+			Stats:   bonsai.ClusterStats{Docs: 1200, ShardsUsed: 4, DataBytesUsed: 65536},
+			State:   bonsai.ClusterStateProvisioned,
+		},
+		{
+			Slug:    "logs-1234567890",
+			Name:    "logs",
+			Plan:    bonsai.Plan{Slug: "sandbox-aws-us-east-1"},
+			Space:   bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+			Release: bonsai.Release{Slug: "opensearch-2.6.0-mt"},
+			State:   bonsai.ClusterStateReadOnly,
+		},
+	}
+
+	s.server = bonsaitest.NewServer(bonsaitest.WithFixtures(fixtures))
+	s.client = s.server.Client()
+
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func (s *CollectorTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestCollectorTestSuite(t *testing.T) {
+	suite.Run(t, new(CollectorTestSuite))
+}
+
+// clusterListRequests returns the number of requests made to list clusters.
+func (s *CollectorTestSuite) clusterListRequests() int {
+	n := 0
+	for _, req := range s.server.Requests() {
+		if req.Method == http.MethodGet && req.Path == bonsai.ClusterAPIBasePath {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *CollectorTestSuite) TestCollect() {
+	c := collector.New(s.client)
+	s.NoError(c.Refresh(context.Background()))
+
+	expected := `
+# HELP bonsai_cluster_docs Number of documents in the cluster's indices.
+# TYPE bonsai_cluster_docs gauge
+bonsai_cluster_docs{plan="sandbox-aws-us-east-1",release="opensearch-2.6.0-mt",slug="logs-1234567890",space="omc/bonsai/us-east-1/common",state="READONLY"} 0
+bonsai_cluster_docs{plan="standard-sm",release="opensearch-2.6.0-mt",slug="search-1234567890",space="omc/bonsai/us-east-1/common",state="PROVISIONED"} 1200
+# HELP bonsai_cluster_shards_used Number of shards the cluster is using.
+# TYPE bonsai_cluster_shards_used gauge
+bonsai_cluster_shards_used{plan="sandbox-aws-us-east-1",release="opensearch-2.6.0-mt",slug="logs-1234567890",space="omc/bonsai/us-east-1/common",state="READONLY"} 0
+bonsai_cluster_shards_used{plan="standard-sm",release="opensearch-2.6.0-mt",slug="search-1234567890",space="omc/bonsai/us-east-1/common",state="PROVISIONED"} 4
+# HELP bonsai_cluster_data_bytes_used Number of bytes the cluster is using on-disk.
+# TYPE bonsai_cluster_data_bytes_used gauge
+bonsai_cluster_data_bytes_used{plan="sandbox-aws-us-east-1",release="opensearch-2.6.0-mt",slug="logs-1234567890",space="omc/bonsai/us-east-1/common",state="READONLY"} 0
+bonsai_cluster_data_bytes_used{plan="standard-sm",release="opensearch-2.6.0-mt",slug="search-1234567890",space="omc/bonsai/us-east-1/common",state="PROVISIONED"} 65536
+# HELP bonsai_clusters Number of clusters on the account, by state.
+# TYPE bonsai_clusters gauge
+bonsai_clusters{state="DEPROVISIONED"} 0
+bonsai_clusters{state="DEPROVISIONING"} 0
+bonsai_clusters{state="DISABLED"} 0
+bonsai_clusters{state="MAINTENANCE"} 0
+bonsai_clusters{state="PROVISIONED"} 1
+bonsai_clusters{state="PROVISIONING"} 0
+bonsai_clusters{state="READONLY"} 1
+bonsai_clusters{state="UPDATING PLAN"} 0
+# HELP bonsai_collector_last_refresh_success Whether the most recent attempt to list clusters succeeded.
+# TYPE bonsai_collector_last_refresh_success gauge
+bonsai_collector_last_refresh_success 1
+`
+	s.NoError(testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"bonsai_cluster_docs",
+		"bonsai_cluster_shards_used",
+		"bonsai_cluster_data_bytes_used",
+		"bonsai_clusters",
+		"bonsai_collector_last_refresh_success",
+	))
+
+	last, err := c.LastRefresh()
+	s.NoError(err)
+	s.False(last.IsZero())
+
+	problems, err := testutil.CollectAndLint(c)
+	s.NoError(err)
+	s.Empty(problems)
+}
+
+func (s *CollectorTestSuite) TestCollectCachesClusters() {
+	c := collector.New(s.client, collector.WithRefreshInterval(time.Hour))
+	s.NoError(c.Refresh(context.Background()))
+
+	registry := prometheus.NewPedanticRegistry()
+	s.NoError(registry.Register(c))
+
+	for range 3 {
+		_, err := registry.Gather()
+		s.NoError(err)
+	}
+	s.Equal(1, s.clusterListRequests(), "clusters are cached between scrapes")
+
+	s.NoError(c.Refresh(context.Background()))
+	s.Equal(2, s.clusterListRequests(), "clusters are listed when refreshed explicitly")
+}
+
+func (s *CollectorTestSuite) TestCollectRefreshesInBackground() {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	client := s.server.Client(bonsai.WithMiddleware(func(next bonsai.Doer) bonsai.Doer {
+		return bonsai.DoerFunc(func(ctx context.Context, req *http.Request) (*bonsai.Response, error) {
+			close(started)
+			<-release
+			return next.Do(ctx, req)
+		})
+	}))
+	c := collector.New(client)
+
+	s.Zero(testutil.CollectAndCount(c, "bonsai_cluster_docs"), "nothing is cached before the first refresh")
+	<-started
+
+	// Scrapes and LastRefresh don't wait for the refresh underway.
+	s.Zero(testutil.CollectAndCount(c, "bonsai_cluster_docs"))
+	last, err := c.LastRefresh()
+	s.True(last.IsZero())
+	s.NoError(err)
+
+	close(release)
+	s.Eventually(func() bool {
+		return testutil.CollectAndCount(c, "bonsai_cluster_docs") == 2
+	}, time.Second, time.Millisecond, "refreshed clusters are exported by later scrapes")
+	s.Equal(1, s.clusterListRequests(), "a single refresh is made at once")
+}
+
+func (s *CollectorTestSuite) TestCollectRefreshFailure() {
+	c := collector.New(s.client, collector.WithRefreshInterval(time.Hour))
+	s.NoError(c.Refresh(context.Background()))
+	s.Equal(2, testutil.CollectAndCount(c, "bonsai_cluster_docs"))
+
+	s.server.FailNext(http.MethodGet, bonsai.ClusterAPIBasePath, http.StatusInternalServerError)
+	s.Error(c.Refresh(context.Background()))
+
+	expected := `
+# HELP bonsai_collector_last_refresh_success Whether the most recent attempt to list clusters succeeded.
+# TYPE bonsai_collector_last_refresh_success gauge
+bonsai_collector_last_refresh_success 0
+`
+	s.NoError(testutil.CollectAndCompare(c, strings.NewReader(expected), "bonsai_collector_last_refresh_success"))
+
+	_, err := c.LastRefresh()
+	s.ErrorIs(err, bonsai.ErrHTTPStatusServerError)
+
+	s.Equal(2, testutil.CollectAndCount(c, "bonsai_cluster_docs"), "stale clusters are still exported")
+	s.Equal(2, s.clusterListRequests(), "failed refreshes aren't retried before the refresh interval")
+}
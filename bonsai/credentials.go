@@ -0,0 +1,199 @@
+package bonsai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// Environment variables read by EnvProvider.
+const (
+	EnvAccessKey   = "BONSAI_API_KEY"
+	EnvAccessToken = "BONSAI_API_TOKEN"
+)
+
+// ErrNoCredentials is returned by a CredentialProvider which has no
+// credentials to supply; for example, because it isn't configured.
+var ErrNoCredentials = errors.New("no credentials available")
+
+// CredentialProvider supplies the credentials used to authorize requests.
+//
+// The Client consults its CredentialProvider each time a request is created
+// with NewRequest, such that rotated credentials are picked up without
+// rebuilding the Client. Implementations must be safe for concurrent use.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (CredentialPair, error)
+}
+
+// WithCredentialProvider configures a Client to authorize requests with
+// the credentials supplied by p.
+func WithCredentialProvider(p CredentialProvider) ClientOption {
+	return func(c *Client) {
+		c.credentials = p
+	}
+}
+
+// Credentials implements CredentialProvider, always supplying c itself.
+func (c CredentialPair) Credentials(_ context.Context) (CredentialPair, error) {
+	return c, nil
+}
+
+// newCredentialPair validates key and token, and pairs them.
+func newCredentialPair(key, token string) (CredentialPair, error) {
+	accessKey, err := NewAccessKey(key)
+	if err != nil {
+		return CredentialPair{}, fmt.Errorf("invalid access key: %w", err)
+	}
+	accessToken, err := NewAccessToken(token)
+	if err != nil {
+		return CredentialPair{}, fmt.Errorf("invalid access token: %w", err)
+	}
+	return CredentialPair{AccessKey: accessKey, AccessToken: accessToken}, nil
+}
+
+// EnvProvider supplies credentials read from environment variables on
+// each call.
+type EnvProvider struct {
+	// KeyVar names the variable holding the access key.
+	// Default: BONSAI_API_KEY.
+	KeyVar string
+	// TokenVar names the variable holding the access token.
+	// Default: BONSAI_API_TOKEN.
+	TokenVar string
+}
+
+// Credentials implements CredentialProvider. If either variable is unset,
+// ErrNoCredentials is returned.
+func (p EnvProvider) Credentials(_ context.Context) (CredentialPair, error) {
+	keyVar, tokenVar := p.KeyVar, p.TokenVar
+	if keyVar == "" {
+		keyVar = EnvAccessKey
+	}
+	if tokenVar == "" {
+		tokenVar = EnvAccessToken
+	}
+
+	key, token := os.Getenv(keyVar), os.Getenv(tokenVar)
+	if key == "" || token == "" {
+		return CredentialPair{}, fmt.Errorf("%w: %s and %s must be set", ErrNoCredentials, keyVar, tokenVar)
+	}
+
+	pair, err := newCredentialPair(key, token)
+	if err != nil {
+		return pair, fmt.Errorf("reading credentials from %s and %s: %w", keyVar, tokenVar, err)
+	}
+	return pair, nil
+}
+
+// FileProvider supplies credentials read from a named profile in a JSON or
+// INI profiles file, which holds "access_key" and "access_token" settings
+// for each profile:
+//
+//	[default]
+//	access_key = ...
+//	access_token = ...
+//
+// The file is read again whenever its modification time or size changes,
+// such that credentials rotated on disk are picked up.
+type FileProvider struct {
+	path    string
+	profile string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	pair    CredentialPair
+	err     error
+}
+
+// NewFileProvider returns a FileProvider reading the named profile from the
+// file at path. An empty profile selects DefaultProfile.
+func NewFileProvider(path, profile string) *FileProvider {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	return &FileProvider{path: path, profile: profile}
+}
+
+// Credentials implements CredentialProvider. If the file or profile
+// doesn't exist, ErrNoCredentials is returned.
+func (p *FileProvider) Credentials(_ context.Context) (CredentialPair, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return CredentialPair{}, fmt.Errorf("%w: %w", ErrNoCredentials, err)
+		}
+		return CredentialPair{}, fmt.Errorf("reading credentials file: %w", err)
+	}
+
+	if !info.ModTime().Equal(p.modTime) || info.Size() != p.size {
+		p.pair, p.err = p.load()
+		p.modTime, p.size = info.ModTime(), info.Size()
+	}
+
+	return p.pair, p.err
+}
+
+func (p *FileProvider) load() (CredentialPair, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return CredentialPair{}, fmt.Errorf("reading credentials file: %w", err)
+	}
+
+	all, err := parseProfiles(p.path, data)
+	if err != nil {
+		return CredentialPair{}, err
+	}
+
+	profile, ok := all[p.profile]
+	if !ok {
+		return CredentialPair{}, fmt.Errorf("%w: profile %q not found in %s", ErrNoCredentials, p.profile, p.path)
+	}
+
+	key, token := profile[profileKeyAccessKey], profile[profileKeyAccessToken]
+	if key == "" || token == "" {
+		return CredentialPair{}, fmt.Errorf(
+			"%w: profile %q in %s must set %s and %s",
+			ErrNoCredentials, p.profile, p.path, profileKeyAccessKey, profileKeyAccessToken,
+		)
+	}
+
+	pair, err := newCredentialPair(key, token)
+	if err != nil {
+		return pair, fmt.Errorf("profile %q in %s: %w", p.profile, p.path, err)
+	}
+	return pair, nil
+}
+
+// ChainProvider supplies credentials from the first of its providers which
+// has them. Providers returning ErrNoCredentials are skipped; any other
+// error is returned immediately.
+type ChainProvider []CredentialProvider
+
+// Credentials implements CredentialProvider.
+func (p ChainProvider) Credentials(ctx context.Context) (CredentialPair, error) {
+	errs := make([]error, 0, len(p))
+	for _, provider := range p {
+		pair, err := provider.Credentials(ctx)
+		switch {
+		case err == nil:
+			return pair, nil
+		case errors.Is(err, ErrNoCredentials):
+			errs = append(errs, err)
+		default:
+			return pair, err
+		}
+	}
+
+	if len(errs) == 0 {
+		return CredentialPair{}, ErrNoCredentials
+	}
+	return CredentialPair{}, errors.Join(errs...)
+}
@@ -0,0 +1,186 @@
+package bonsai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+func (s *ClientMockTestSuite) TestEnvProvider() {
+	ctx := context.Background()
+
+	s.Run("unset", func() {
+		s.T().Setenv(bonsai.EnvAccessKey, "")
+		s.T().Setenv(bonsai.EnvAccessToken, "")
+
+		_, err := bonsai.EnvProvider{}.Credentials(ctx)
+		s.ErrorIs(err, bonsai.ErrNoCredentials)
+	})
+
+	s.Run("default variables", func() {
+		s.T().Setenv(bonsai.EnvAccessKey, "env-key")
+		s.T().Setenv(bonsai.EnvAccessToken, "env-token")
+
+		pair, err := bonsai.EnvProvider{}.Credentials(ctx)
+		s.NoError(err)
+		s.Equal(bonsai.CredentialPair{AccessKey: "env-key", AccessToken: "env-token"}, pair)
+	})
+
+	s.Run("custom variables", func() {
+		s.T().Setenv("CUSTOM_KEY", "custom-key")
+		s.T().Setenv("CUSTOM_TOKEN", "custom-token")
+
+		pair, err := bonsai.EnvProvider{KeyVar: "CUSTOM_KEY", TokenVar: "CUSTOM_TOKEN"}.Credentials(ctx)
+		s.NoError(err)
+		s.Equal(bonsai.CredentialPair{AccessKey: "custom-key", AccessToken: "custom-token"}, pair)
+	})
+
+	s.Run("invalid", func() {
+		s.T().Setenv(bonsai.EnvAccessKey, "bad\nkey")
+		s.T().Setenv(bonsai.EnvAccessToken, "env-token")
+
+		_, err := bonsai.EnvProvider{}.Credentials(ctx)
+		s.Error(err)
+		s.NotErrorIs(err, bonsai.ErrNoCredentials)
+	})
+}
+
+func (s *ClientMockTestSuite) TestFileProvider() {
+	ctx := context.Background()
+	dir := s.T().TempDir()
+
+	s.Run("missing file", func() {
+		_, err := bonsai.NewFileProvider(filepath.Join(dir, "missing"), "").Credentials(ctx)
+		s.ErrorIs(err, bonsai.ErrNoCredentials)
+	})
+
+	s.Run("ini", func() {
+		path := filepath.Join(dir, "credentials")
+		s.writeFile(path, `
+# Bonsai credentials
+[default]
+access_key = default-key
+access_token = default-token
+
+[profile staging]
+access_key = "staging-key"
+access_token = "staging-token"
+`)
+
+		pair, err := bonsai.NewFileProvider(path, "").Credentials(ctx)
+		s.NoError(err)
+		s.Equal(bonsai.CredentialPair{AccessKey: "default-key", AccessToken: "default-token"}, pair)
+
+		pair, err = bonsai.NewFileProvider(path, "staging").Credentials(ctx)
+		s.NoError(err)
+		s.Equal(bonsai.CredentialPair{AccessKey: "staging-key", AccessToken: "staging-token"}, pair)
+
+		_, err = bonsai.NewFileProvider(path, "production").Credentials(ctx)
+		s.ErrorIs(err, bonsai.ErrNoCredentials)
+	})
+
+	s.Run("json", func() {
+		path := filepath.Join(dir, "credentials.json")
+		s.writeFile(path, `{"default": {"access_key": "json-key", "access_token": "json-token"}}`)
+
+		pair, err := bonsai.NewFileProvider(path, "").Credentials(ctx)
+		s.NoError(err)
+		s.Equal(bonsai.CredentialPair{AccessKey: "json-key", AccessToken: "json-token"}, pair)
+	})
+
+	s.Run("malformed", func() {
+		path := filepath.Join(dir, "malformed")
+		s.writeFile(path, "[default\naccess_key = key\n")
+
+		_, err := bonsai.NewFileProvider(path, "").Credentials(ctx)
+		s.ErrorContains(err, "unterminated section header")
+		s.NotErrorIs(err, bonsai.ErrNoCredentials)
+	})
+
+	s.Run("rotation", func() {
+		path := filepath.Join(dir, "rotating")
+		s.writeFile(path, "[default]\naccess_key = old-key\naccess_token = old-token\n")
+
+		provider := bonsai.NewFileProvider(path, "")
+		pair, err := provider.Credentials(ctx)
+		s.NoError(err)
+		s.Equal(bonsai.AccessKey("old-key"), pair.AccessKey)
+
+		s.writeFile(path, "[default]\naccess_key = new-key\naccess_token = new-token\n")
+		// Ensure the change is observable on filesystems with a coarse
+		// modification time resolution.
+		later := time.Now().Add(time.Minute)
+		s.NoError(os.Chtimes(path, later, later))
+
+		pair, err = provider.Credentials(ctx)
+		s.NoError(err)
+		s.Equal(bonsai.CredentialPair{AccessKey: "new-key", AccessToken: "new-token"}, pair)
+	})
+}
+
+func (s *ClientMockTestSuite) TestChainProvider() {
+	ctx := context.Background()
+	missing := bonsai.NewFileProvider(filepath.Join(s.T().TempDir(), "missing"), "")
+	pair := bonsai.CredentialPair{AccessKey: "chain-key", AccessToken: "chain-token"}
+
+	got, err := bonsai.ChainProvider{missing, pair}.Credentials(ctx)
+	s.NoError(err)
+	s.Equal(pair, got, "providers without credentials are skipped")
+
+	_, err = bonsai.ChainProvider{missing}.Credentials(ctx)
+	s.ErrorIs(err, bonsai.ErrNoCredentials)
+
+	_, err = bonsai.ChainProvider{}.Credentials(ctx)
+	s.ErrorIs(err, bonsai.ErrNoCredentials)
+
+	failure := errors.New("vault unavailable")
+	_, err = bonsai.ChainProvider{failingProvider{failure}, pair}.Credentials(ctx)
+	s.ErrorIs(err, failure, "other errors stop the chain")
+}
+
+func (s *ClientMockTestSuite) TestClient_WithCredentialProvider() {
+	ctx := context.Background()
+	path := filepath.Join(s.T().TempDir(), "credentials")
+	s.writeFile(path, "[default]\naccess_key = first-key\naccess_token = first-token\n")
+
+	client := bonsai.NewClient(bonsai.WithCredentialProvider(bonsai.NewFileProvider(path, "")))
+
+	req, err := client.NewRequest(ctx, http.MethodGet, bonsai.PlanAPIBasePath, nil)
+	s.NoError(err)
+	key, token, ok := req.BasicAuth()
+	s.True(ok)
+	s.Equal("first-key", key)
+	s.Equal("first-token", token)
+
+	s.writeFile(path, "[default]\naccess_key = second-key\naccess_token = second-token\n")
+	later := time.Now().Add(time.Minute)
+	s.NoError(os.Chtimes(path, later, later))
+
+	req, err = client.NewRequest(ctx, http.MethodGet, bonsai.PlanAPIBasePath, nil)
+	s.NoError(err)
+	key, token, ok = req.BasicAuth()
+	s.True(ok)
+	s.Equal("second-key", key, "rotated credentials are used without rebuilding the client")
+	s.Equal("second-token", token)
+
+	s.NoError(os.Remove(path))
+	_, err = client.NewRequest(ctx, http.MethodGet, bonsai.PlanAPIBasePath, nil)
+	s.ErrorIs(err, bonsai.ErrNoCredentials)
+}
+
+func (s *ClientMockTestSuite) writeFile(path, contents string) {
+	s.NoError(os.WriteFile(path, []byte(contents), 0o600))
+}
+
+type failingProvider struct {
+	err error
+}
+
+func (p failingProvider) Credentials(context.Context) (bonsai.CredentialPair, error) {
+	return bonsai.CredentialPair{}, p.err
+}
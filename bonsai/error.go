@@ -1 +1,129 @@
 package bonsai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrInvalidOption is matched, via errors.Is, by every OptionError.
+var ErrInvalidOption = errors.New("invalid option")
+
+// OptionError reports an option value that was rejected client-side,
+// before any request was made to the API.
+type OptionError struct {
+	// Field is the name of the rejected option.
+	Field string
+	// Value is the rejected option value.
+	Value string
+	// Err describes why the value was rejected.
+	Err error
+}
+
+func (e OptionError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Field, e.Value, e.Err)
+}
+
+func (e OptionError) Is(target error) bool {
+	return target == ErrInvalidOption
+}
+
+func (e OptionError) Unwrap() error {
+	return e.Err
+}
+
+// maxAPIErrorBodyLength is the maximum length of the raw response body
+// held by an APIError.
+const maxAPIErrorBodyLength = 512
+
+// APIError describes an error response received from the API, along with
+// details of the request which caused it.
+//
+// APIError embeds, and unwraps to, the ResponseError parsed from the
+// response body, and so matches the ErrHTTPStatus* errors via errors.Is.
+//
+//	var apiErr bonsai.APIError
+//	if errors.As(err, &apiErr) {
+//		log.Printf("request %s failed: %v", apiErr.RequestID, apiErr.Errors)
+//	}
+type APIError struct {
+	ResponseError
+
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the URL path of the request.
+	Path string
+	// RequestID is the identifier the API assigned to the request, if any.
+	RequestID string
+	// Body holds the raw response body, truncated to a reasonable length.
+	Body string
+	// Attempts is the number of attempts made, per the Client's RetryPolicy,
+	// before the request was abandoned.
+	Attempts int
+	// RetryAfter is the delay requested by the response's Retry-After
+	// header, if any.
+	RetryAfter time.Duration
+}
+
+func newAPIError(req *http.Request, resp *Response) APIError {
+	apiErr := APIError{
+		ResponseError: ResponseError{Status: resp.StatusCode},
+		Method:        req.Method,
+		Path:          req.URL.Path,
+		RequestID:     resp.Header.Get(HeaderRequestID),
+		Body:          truncate(resp.BodyBuf.String(), maxAPIErrorBodyLength),
+		Attempts:      1,
+	}
+
+	if resp.isJSON() {
+		// Suppress unmarshaling errors in the event that the response didn't
+		// contain a message; the raw body is retained instead.
+		_ = json.Unmarshal(resp.BodyBuf.Bytes(), &apiErr.ResponseError)
+		// The status of the response takes precedence over that in its body.
+		apiErr.Status = resp.StatusCode
+	}
+
+	if d, ok := retryAfter(resp.Header, time.Now()); ok {
+		apiErr.RetryAfter = d
+	}
+
+	return apiErr
+}
+
+func (e APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s: %d %s", e.Method, e.Path, e.Status, http.StatusText(e.Status))
+
+	switch {
+	case len(e.Errors) > 0:
+		fmt.Fprintf(&b, ": %s", strings.Join(e.Errors, "; "))
+	case strings.TrimSpace(e.Body) != "":
+		fmt.Fprintf(&b, ": %s", strings.TrimSpace(e.Body))
+	}
+
+	if e.Attempts > 1 {
+		fmt.Fprintf(&b, " (after %d attempts)", e.Attempts)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+
+	return b.String()
+}
+
+func (e APIError) Unwrap() error {
+	return e.ResponseError
+}
+
+// truncate shortens s to at most n bytes, without splitting a UTF-8
+// encoded rune, marking it as truncated with an ellipsis.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "") + "…"
+}
@@ -0,0 +1,142 @@
+package bonsai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+func (s *ClientMockTestSuite) TestAPIError() {
+	const prefix = "/api-error"
+
+	s.serveMux.Get(prefix+"/unavailable", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(bonsai.HeaderRequestID, "5c4bd6a1-88c8-4f0e-a6a2-d9d0e5b1c1a1")
+		w.Header().Set(bonsai.HeaderRetryAfter, "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, err := fmt.Fprint(w, "upstream connect error "+strings.Repeat("x", 1024))
+		s.NoError(err, "wrote error response")
+	})
+
+	client := s.newRetryingClient(bonsai.RetryPolicy{
+		MaxAttempts:   2,
+		BaseDelay:     time.Millisecond,
+		RetryStatuses: []int{http.StatusServiceUnavailable},
+	})
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, prefix+"/unavailable", nil)
+	s.NoError(err, "created request")
+
+	_, err = client.Do(context.Background(), req)
+	s.ErrorIs(err, bonsai.ErrHTTPStatusServerError)
+	s.ErrorAs(err, &bonsai.ResponseError{}, "APIError unwraps to ResponseError")
+
+	apiErr := bonsai.APIError{}
+	s.ErrorAs(err, &apiErr)
+	s.Equal(http.MethodGet, apiErr.Method)
+	s.Equal(prefix+"/unavailable", apiErr.Path)
+	s.Equal(http.StatusServiceUnavailable, apiErr.Status)
+	s.Equal("5c4bd6a1-88c8-4f0e-a6a2-d9d0e5b1c1a1", apiErr.RequestID)
+	s.Equal(2, apiErr.Attempts)
+	s.Equal(time.Duration(0), apiErr.RetryAfter)
+	s.True(strings.HasPrefix(apiErr.Body, "upstream connect error"))
+	s.Less(len(apiErr.Body), 1024, "body is truncated")
+	s.ErrorContains(err, "GET /api-error/unavailable: 503 Service Unavailable: upstream connect error")
+	s.ErrorContains(err, "(after 2 attempts) (request id 5c4bd6a1-88c8-4f0e-a6a2-d9d0e5b1c1a1)")
+}
+
+func (s *ClientMockTestSuite) TestAPIError_WrappedByMiddleware() {
+	const prefix = "/api-error-wrapped"
+
+	s.serveMux.Get(prefix+"/unavailable", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	client := bonsai.NewClient(
+		bonsai.WithEndpoint(s.server.URL),
+		bonsai.WithRetryPolicy(bonsai.RetryPolicy{
+			MaxAttempts:   3,
+			BaseDelay:     time.Millisecond,
+			RetryStatuses: []int{http.StatusServiceUnavailable},
+		}),
+		bonsai.WithMiddleware(func(next bonsai.Doer) bonsai.Doer {
+			return bonsai.DoerFunc(func(ctx context.Context, req *http.Request) (*bonsai.Response, error) {
+				resp, err := next.Do(ctx, req)
+				if err != nil {
+					return resp, fmt.Errorf("wrapped: %w", err)
+				}
+				return resp, nil
+			})
+		}),
+	)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, prefix+"/unavailable", nil)
+	s.NoError(err, "created request")
+
+	_, err = client.Do(context.Background(), req)
+	apiErr := bonsai.APIError{}
+	s.ErrorAs(err, &apiErr)
+	s.Equal(3, apiErr.Attempts, "attempts are recorded through wrapping middleware")
+}
+
+func (s *ClientMockTestSuite) TestAPIError_Sentinels() {
+	testCases := []struct {
+		status int
+		expect error
+	}{
+		{status: http.StatusBadRequest, expect: bonsai.ErrHTTPStatusBadRequest},
+		{status: http.StatusUnauthorized, expect: bonsai.ErrHTTPStatusUnauthorized},
+		{status: http.StatusPaymentRequired, expect: bonsai.ErrHTTPStatusPaymentRequired},
+		{status: http.StatusForbidden, expect: bonsai.ErrHTTPStatusForbidden},
+		{status: http.StatusNotFound, expect: bonsai.ErrHTTPStatusNotFound},
+		{status: http.StatusConflict, expect: bonsai.ErrHTTPStatusConflict},
+		{status: http.StatusUnprocessableEntity, expect: bonsai.ErrHTTPStatusUnprocessableEntity},
+		{status: http.StatusInternalServerError, expect: bonsai.ErrHTTPStatusServerError},
+		{status: http.StatusGatewayTimeout, expect: bonsai.ErrHTTPStatusServerError},
+	}
+
+	for _, tc := range testCases {
+		s.Run(http.StatusText(tc.status), func() {
+			p := fmt.Sprintf("/api-error/status/%d", tc.status)
+			s.serveMux.Get(p, func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+				w.WriteHeader(tc.status)
+				_, err := fmt.Fprintf(w, `{"errors": ["failed with %d"], "status": %d}`, tc.status, tc.status)
+				s.NoError(err, "wrote error response")
+			})
+
+			req, err := s.client.NewRequest(context.Background(), http.MethodGet, p, nil)
+			s.NoError(err, "created request")
+
+			_, err = s.client.Do(context.Background(), req)
+			s.ErrorIs(err, tc.expect)
+			s.NotErrorIs(err, bonsai.ErrNetwork)
+
+			apiErr := bonsai.APIError{}
+			s.ErrorAs(err, &apiErr)
+			s.Equal([]string{fmt.Sprintf("failed with %d", tc.status)}, apiErr.Errors)
+			s.Equal(1, apiErr.Attempts)
+		})
+	}
+}
+
+func (s *ClientMockTestSuite) TestClient_NetworkError() {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := bonsai.NewClient(bonsai.WithEndpoint(server.URL))
+
+	_, err := client.Plan.All(context.Background())
+	s.ErrorIs(err, bonsai.ErrNetwork)
+	s.False(errors.As(err, &bonsai.APIError{}), "no response was received")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Plan.All(ctx)
+	s.NotErrorIs(err, bonsai.ErrNetwork, "cancellation isn't a network error")
+}
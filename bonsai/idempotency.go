@@ -0,0 +1,234 @@
+package bonsai
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HeaderIdempotencyKey holds a client-generated key identifying a request,
+// such that servers supporting it can recognize, and not repeat, a request
+// that's sent more than once.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// Idempotent creation defaults.
+const (
+	// DefaultIdempotentCreateAttempts is the default number of times a
+	// create request is issued by CreateIdempotent.
+	DefaultIdempotentCreateAttempts = 3
+	// DefaultIdempotentLookupTimeout is the default time allowed to look up
+	// a Cluster by name after an ambiguous failure.
+	DefaultIdempotentLookupTimeout = 30 * time.Second
+)
+
+// ErrDuplicateClusterName is returned when more than one Cluster holds the
+// name of a Cluster being created idempotently, such that it's unclear
+// which, if any, was created.
+var ErrDuplicateClusterName = errors.New("multiple clusters have the same name")
+
+// randReader is the source of idempotency keys; tests replace it.
+var randReader io.Reader = rand.Reader
+
+// NewIdempotencyKey returns a new, random, idempotency key.
+func NewIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(randReader, b); err != nil {
+		return "", fmt.Errorf("generating idempotency key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IdempotencyOpts configures CreateIdempotent and Ensure.
+type IdempotencyOpts struct {
+	// Key is sent in the Idempotency-Key header of every create request.
+	// Default: a key generated by NewIdempotencyKey.
+	Key string
+	// MaxAttempts is the maximum number of create requests issued.
+	// Default: DefaultIdempotentCreateAttempts.
+	MaxAttempts int
+	// LookupTimeout bounds each lookup of the Cluster by name following an
+	// ambiguous failure. Lookups aren't canceled with the context passed
+	// to CreateIdempotent, as they're most needed once it's done.
+	// Default: DefaultIdempotentLookupTimeout.
+	LookupTimeout time.Duration
+}
+
+func (o IdempotencyOpts) withDefaults() (IdempotencyOpts, error) {
+	if o.Key == "" {
+		key, err := NewIdempotencyKey()
+		if err != nil {
+			return o, err
+		}
+		o.Key = key
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultIdempotentCreateAttempts
+	}
+	if o.LookupTimeout <= 0 {
+		o.LookupTimeout = DefaultIdempotentLookupTimeout
+	}
+	return o, nil
+}
+
+// ClusterEnsureResult is the result of CreateIdempotent and Ensure.
+type ClusterEnsureResult struct {
+	// Created holds the response to the create request, if one was
+	// received.
+	Created ClustersResultCreate
+	// Found reports whether, rather than receiving a response to the create
+	// request, a Cluster with the requested name was found.
+	//
+	// As the API only reveals a Cluster's credentials in response to the
+	// request creating it, they're unavailable for found Clusters.
+	Found bool
+	// Cluster holds the Cluster found, if Found is true.
+	Cluster Cluster
+	// IdempotencyKey is the key sent with create requests, if any.
+	IdempotencyKey string
+}
+
+// CreateIdempotent requests a new Cluster be created, as Create, while
+// guarding against creating duplicates when the outcome of a request is
+// ambiguous; for example, when it times out after being sent.
+//
+// Each create request carries the same Idempotency-Key header, for servers
+// which support it. Failing that, after an ambiguous failure (a network
+// error, server error or timeout), Clusters are looked up by name with
+// [ClusterAllOpts.Query]. If one is found, it's returned, with Found set;
+// otherwise, the request is issued again, up to opt.MaxAttempts times.
+//
+// Cluster names aren't required to be unique; CreateIdempotent assumes
+// that no other Cluster holds opt.Name. Use Ensure to also check before the
+// first request.
+func (c *ClusterClient) CreateIdempotent(ctx context.Context, opt ClusterCreateOpts, idem IdempotencyOpts) (
+	ClusterEnsureResult,
+	error,
+) {
+	idem, keyErr := idem.withDefaults()
+	if keyErr != nil {
+		return ClusterEnsureResult{}, keyErr
+	}
+	result := ClusterEnsureResult{IdempotencyKey: idem.Key}
+
+	for attempt := 1; ; attempt++ {
+		created, err := c.create(ctx, opt, idem.Key)
+		if err == nil {
+			result.Created = created
+			return result, nil
+		}
+		if !ambiguousCreateError(err) {
+			return result, err
+		}
+
+		cluster, found, lookupErr := c.lookupByName(ctx, opt.Name, idem.LookupTimeout)
+		switch {
+		case lookupErr != nil:
+			return result, errors.Join(err, lookupErr)
+		case found:
+			result.Found, result.Cluster = true, cluster
+			return result, nil
+		case attempt >= idem.MaxAttempts || ctx.Err() != nil:
+			return result, err
+		}
+
+		if sleepErr := sleepContext(ctx, c.retryPolicy.delay(attempt, nil)); sleepErr != nil {
+			return result, errors.Join(
+				fmt.Errorf("failed while awaiting create attempt %d: %w", attempt+1, sleepErr),
+				err,
+			)
+		}
+	}
+}
+
+// Ensure returns the Cluster named opt.Name, creating it per
+// CreateIdempotent if no such Cluster exists.
+//
+// If the Cluster already exists, the result has Found set; its plan, space
+// and release aren't compared with opt.
+func (c *ClusterClient) Ensure(ctx context.Context, opt ClusterCreateOpts, idem IdempotencyOpts) (
+	ClusterEnsureResult,
+	error,
+) {
+	if err := opt.Valid(); err != nil {
+		return ClusterEnsureResult{}, fmt.Errorf("invalid create options (%v): %w", opt, err)
+	}
+
+	cluster, found, err := c.findByName(ctx, opt.Name)
+	if err != nil {
+		return ClusterEnsureResult{}, err
+	}
+	if found {
+		return ClusterEnsureResult{Found: true, Cluster: cluster}, nil
+	}
+
+	return c.CreateIdempotent(ctx, opt, idem)
+}
+
+// lookupByName performs findByName following a failed create request,
+// allowing it timeout even if ctx is done.
+func (c *ClusterClient) lookupByName(ctx context.Context, name string, timeout time.Duration) (Cluster, bool, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	cluster, found, err := c.findByName(ctx, name)
+	if err != nil {
+		return cluster, found, fmt.Errorf("looking up cluster %q after failed create: %w", name, err)
+	}
+	return cluster, found, nil
+}
+
+// findByName returns the Cluster named name, disregarding those being
+// deprovisioned. If more than one Cluster is named name,
+// ErrDuplicateClusterName is returned.
+func (c *ClusterClient) findByName(ctx context.Context, name string) (Cluster, bool, error) {
+	candidates, err := c.Iter(ClusterAllOpts{Query: name}, PageOpts{}).All(ctx)
+	if err != nil {
+		return Cluster{}, false, fmt.Errorf("listing clusters named %q: %w", name, err)
+	}
+
+	var matches []Cluster
+	for _, cluster := range candidates {
+		if cluster.Name != name ||
+			cluster.State == ClusterStateDeprovisioning || cluster.State == ClusterStateDeprovisioned {
+			continue
+		}
+		matches = append(matches, cluster)
+	}
+
+	switch len(matches) {
+	case 0:
+		return Cluster{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	default:
+		return Cluster{}, false, fmt.Errorf("%w: %q", ErrDuplicateClusterName, name)
+	}
+}
+
+// ambiguousCreateError reports whether err leaves it unknown whether a
+// create request took effect.
+func ambiguousCreateError(err error) bool {
+	// The cluster was created, but its credentials weren't stored.
+	if errors.Is(err, ErrCredentialSink) {
+		return false
+	}
+
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status >= http.StatusInternalServerError || apiErr.Status == http.StatusRequestTimeout
+	}
+
+	var optErr OptionError
+	if errors.As(err, &optErr) {
+		return false
+	}
+
+	return errors.Is(err, ErrNetwork) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,42 @@
+package bonsai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing/iotest"
+)
+
+func (s *ClientImplTestSuite) TestIdempotencyKeyFailure() {
+	const prefix = "/idempotency-key-failure"
+
+	requests := 0
+	s.serveMux.Post(prefix+ClusterAPIBasePath, func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.Header().Set(HTTPHeaderContentType, HTTPContentTypeJSON)
+		w.WriteHeader(http.StatusAccepted)
+		_, err := w.Write([]byte(`{"message": "Your cluster is being provisioned.", "status": 202}`))
+		s.NoError(err, "wrote create response")
+	})
+
+	errEntropy := errors.New("entropy unavailable")
+	defer func(r io.Reader) { randReader = r }(randReader)
+	randReader = iotest.ErrReader(errEntropy)
+
+	_, err := NewIdempotencyKey()
+	s.ErrorIs(err, errEntropy)
+
+	client := NewClient(WithEndpoint(s.server.URL + prefix))
+	_, err = client.Cluster.CreateIdempotent(context.Background(), ClusterCreateOpts{Name: "idempotent"}, IdempotencyOpts{})
+	s.ErrorIs(err, errEntropy)
+	s.Zero(requests, "no create request is made without a key")
+
+	_, err = client.Cluster.CreateIdempotent(
+		context.Background(),
+		ClusterCreateOpts{Name: "idempotent"},
+		IdempotencyOpts{Key: "provided-key", MaxAttempts: 1},
+	)
+	s.NoError(err, "provided keys aren't generated")
+	s.Equal(1, requests)
+}
@@ -0,0 +1,234 @@
+package bonsai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// fakeClusters serves a minimal Clusters API, whose create requests may be
+// made to fail, before or after creating the cluster.
+type fakeClusters struct {
+	mu       sync.Mutex
+	clusters []bonsai.Cluster
+	keys     []string
+	lookups  int
+	// respond, if set, handles the create request after its cluster, if
+	// created, is recorded, in place of a successful response.
+	respond func(w http.ResponseWriter, attempt int) (created bool)
+}
+
+func (f *fakeClusters) register(s *ClientMockTestSuite, prefix string) {
+	s.serveMux.Post(prefix+bonsai.ClusterAPIBasePath, func(w http.ResponseWriter, r *http.Request) {
+		opt := bonsai.ClusterCreateOpts{}
+		s.NoError(json.NewDecoder(r.Body).Decode(&opt), "decoded create options")
+
+		f.mu.Lock()
+		f.keys = append(f.keys, r.Header.Get(bonsai.HeaderIdempotencyKey))
+		attempt := len(f.keys)
+		f.mu.Unlock()
+
+		created := true
+		if f.respond != nil {
+			created = f.respond(w, attempt)
+		}
+		if created {
+			f.mu.Lock()
+			f.clusters = append(f.clusters, bonsai.Cluster{
+				Slug:  fmt.Sprintf("%s-%d", opt.Name, attempt),
+				Name:  opt.Name,
+				State: bonsai.ClusterStateProvisioning,
+			})
+			f.mu.Unlock()
+		}
+		if f.respond == nil {
+			w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+			w.WriteHeader(http.StatusAccepted)
+			_, err := fmt.Fprint(w, `{"message": "Your cluster is being provisioned.", "access": {"user": "u", "pass": "p"}}`)
+			s.NoError(err, "wrote create response")
+		}
+	})
+	s.serveMux.Get(prefix+bonsai.ClusterAPIBasePath, func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.lookups++
+
+		var matches []bonsai.Cluster
+		for _, c := range f.clusters {
+			if strings.Contains(c.Name, r.URL.Query().Get("q")) {
+				matches = append(matches, c)
+			}
+		}
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		s.NoError(json.NewEncoder(w).Encode(bonsai.ClustersResultList{Clusters: matches}), "wrote list response")
+	})
+}
+
+func fail(w http.ResponseWriter, status int) {
+	w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, `{"errors": ["%s"], "status": %d}`, http.StatusText(status), status)
+}
+
+func (s *ClientMockTestSuite) TestClusterClient_CreateIdempotent() {
+	ctx := context.Background()
+	opt := bonsai.ClusterCreateOpts{Name: "idempotent", Plan: "sandbox-aws-us-east-1"}
+
+	newClient := func(prefix string) *bonsai.Client {
+		return bonsai.NewClient(
+			bonsai.WithEndpoint(s.server.URL+prefix),
+			bonsai.WithRetryPolicy(bonsai.RetryPolicy{MaxAttempts: 1}),
+		)
+	}
+
+	s.Run("created", func() {
+		const prefix = "/idempotent-created"
+		f := &fakeClusters{}
+		f.register(s, prefix)
+
+		result, err := newClient(prefix).Cluster.CreateIdempotent(ctx, opt, bonsai.IdempotencyOpts{})
+		s.NoError(err)
+		s.False(result.Found)
+		s.Equal("u", result.Created.Access.Username)
+		s.NotEmpty(result.IdempotencyKey)
+		s.Equal([]string{result.IdempotencyKey}, f.keys, "idempotency key is sent")
+		s.Zero(f.lookups)
+	})
+
+	s.Run("retried after failure before creation", func() {
+		const prefix = "/idempotent-retried"
+		f := &fakeClusters{respond: func(w http.ResponseWriter, attempt int) bool {
+			if attempt == 1 {
+				fail(w, http.StatusServiceUnavailable)
+				return false
+			}
+			w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = fmt.Fprint(w, `{"message": "Your cluster is being provisioned."}`)
+			return true
+		}}
+		f.register(s, prefix)
+
+		result, err := newClient(prefix).Cluster.CreateIdempotent(ctx, opt, bonsai.IdempotencyOpts{Key: "fixed-key"})
+		s.NoError(err)
+		s.False(result.Found)
+		s.Equal("Your cluster is being provisioned.", result.Created.Message)
+		s.Equal([]string{"fixed-key", "fixed-key"}, f.keys, "the same key is sent with each attempt")
+		s.Equal(1, f.lookups, "clusters are looked up before the request is issued again")
+		s.Len(f.clusters, 1)
+	})
+
+	s.Run("found after failure following creation", func() {
+		const prefix = "/idempotent-found"
+		f := &fakeClusters{respond: func(w http.ResponseWriter, _ int) bool {
+			fail(w, http.StatusGatewayTimeout)
+			return true
+		}}
+		f.register(s, prefix)
+
+		result, err := newClient(prefix).Cluster.CreateIdempotent(ctx, opt, bonsai.IdempotencyOpts{})
+		s.NoError(err)
+		s.True(result.Found)
+		s.Equal("idempotent-1", result.Cluster.Slug)
+		s.Len(f.keys, 1, "no duplicate is created")
+	})
+
+	s.Run("found after timeout", func() {
+		const prefix = "/idempotent-timeout"
+		release := make(chan struct{})
+		f := &fakeClusters{respond: func(_ http.ResponseWriter, _ int) bool {
+			<-release
+			return true
+		}}
+		f.register(s, prefix)
+		defer close(release)
+
+		// Record the cluster as created before the response is abandoned.
+		f.clusters = append(f.clusters, bonsai.Cluster{Slug: "idempotent-1", Name: opt.Name})
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		result, err := newClient(prefix).Cluster.CreateIdempotent(timeoutCtx, opt, bonsai.IdempotencyOpts{})
+		s.NoError(err, "the cluster is looked up despite the context being done")
+		s.True(result.Found)
+		s.Equal("idempotent-1", result.Cluster.Slug)
+	})
+
+	s.Run("attempts exhausted", func() {
+		const prefix = "/idempotent-exhausted"
+		f := &fakeClusters{respond: func(w http.ResponseWriter, _ int) bool {
+			fail(w, http.StatusBadGateway)
+			return false
+		}}
+		f.register(s, prefix)
+
+		_, err := newClient(prefix).Cluster.CreateIdempotent(ctx, opt, bonsai.IdempotencyOpts{MaxAttempts: 2})
+		s.ErrorIs(err, bonsai.ErrHTTPStatusServerError)
+		s.Len(f.keys, 2)
+		s.Equal(2, f.lookups)
+	})
+
+	s.Run("unambiguous failure", func() {
+		const prefix = "/idempotent-rejected"
+		f := &fakeClusters{respond: func(w http.ResponseWriter, _ int) bool {
+			fail(w, http.StatusUnprocessableEntity)
+			return false
+		}}
+		f.register(s, prefix)
+
+		_, err := newClient(prefix).Cluster.CreateIdempotent(ctx, opt, bonsai.IdempotencyOpts{})
+		s.ErrorIs(err, bonsai.ErrHTTPStatusUnprocessableEntity)
+		s.Len(f.keys, 1)
+		s.Zero(f.lookups, "clusters aren't looked up after a definite failure")
+	})
+
+	s.Run("duplicate names", func() {
+		const prefix = "/idempotent-duplicates"
+		f := &fakeClusters{
+			clusters: []bonsai.Cluster{{Slug: "a", Name: opt.Name}, {Slug: "b", Name: opt.Name}},
+			respond: func(w http.ResponseWriter, _ int) bool {
+				fail(w, http.StatusInternalServerError)
+				return false
+			},
+		}
+		f.register(s, prefix)
+
+		_, err := newClient(prefix).Cluster.CreateIdempotent(ctx, opt, bonsai.IdempotencyOpts{})
+		s.ErrorIs(err, bonsai.ErrHTTPStatusServerError)
+		s.ErrorIs(err, bonsai.ErrDuplicateClusterName)
+	})
+}
+
+func (s *ClientMockTestSuite) TestClusterClient_Ensure() {
+	const prefix = "/ensure"
+	ctx := context.Background()
+
+	f := &fakeClusters{clusters: []bonsai.Cluster{
+		{Slug: "existing-1", Name: "existing", State: bonsai.ClusterStateProvisioned},
+		{Slug: "existing-0", Name: "existing", State: bonsai.ClusterStateDeprovisioned},
+		{Slug: "existing-copy-1", Name: "existing copy", State: bonsai.ClusterStateProvisioned},
+	}}
+	f.register(s, prefix)
+	client := bonsai.NewClient(bonsai.WithEndpoint(s.server.URL + prefix))
+
+	result, err := client.Cluster.Ensure(ctx, bonsai.ClusterCreateOpts{Name: "existing"}, bonsai.IdempotencyOpts{})
+	s.NoError(err)
+	s.True(result.Found)
+	s.Equal("existing-1", result.Cluster.Slug, "deprovisioned clusters and partial matches are disregarded")
+	s.Empty(f.keys, "existing clusters aren't created")
+
+	result, err = client.Cluster.Ensure(ctx, bonsai.ClusterCreateOpts{Name: "missing"}, bonsai.IdempotencyOpts{})
+	s.NoError(err)
+	s.False(result.Found)
+	s.Len(f.keys, 1, "missing clusters are created")
+
+	_, err = client.Cluster.Ensure(ctx, bonsai.ClusterCreateOpts{}, bonsai.IdempotencyOpts{})
+	s.ErrorContains(err, "name can't be empty")
+}
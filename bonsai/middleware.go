@@ -0,0 +1,185 @@
+package bonsai
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Doer performs a single attempt at an HTTP request against the API, and
+// decodes its response.
+type Doer interface {
+	Do(ctx context.Context, req *http.Request) (*Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doers.
+type DoerFunc func(ctx context.Context, req *http.Request) (*Response, error)
+
+// Do calls f(ctx, req).
+func (f DoerFunc) Do(ctx context.Context, req *http.Request) (*Response, error) {
+	return f(ctx, req)
+}
+
+// Middleware wraps a Doer, to observe or alter requests made by the Client.
+//
+// Middleware is invoked once per attempt at a request, after the attempt
+// has cleared the Client's rate limits. Details of the attempt are held by
+// the RequestInfo available from the context passed to Do.
+//
+// Errors returned by next are already decoded; error responses from the API
+// are returned as an APIError.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware configures middleware to wrap each attempt at a request
+// made by the Client. Middleware is applied in the order given, such that
+// the first is outermost, and sees each attempt first.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// RequestInfo describes a single attempt at a request.
+type RequestInfo struct {
+	// Operation is the logical name of the Client method making the request,
+	// for example "Cluster.Create". It's empty for requests made directly
+	// with Client.Do.
+	Operation string
+	// Cluster is the slug of the cluster the operation applies to, if known.
+	Cluster string
+	// Plan is the slug of the plan the operation applies to, if known.
+	Plan string
+	// Attempt is the number of the attempt, starting at 1, per the Client's
+	// RetryPolicy.
+	Attempt int
+	// RateLimitWait is the time the attempt spent waiting on the Client's
+	// rate limiters before being sent.
+	RateLimitWait time.Duration
+}
+
+type (
+	operationKey   struct{}
+	requestInfoKey struct{}
+)
+
+// withOperation returns a copy of ctx, which describes the logical
+// operation its requests are made on behalf of. Only the Operation,
+// Cluster and Plan fields of op are used.
+func withOperation(ctx context.Context, op RequestInfo) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+func operationFromContext(ctx context.Context) RequestInfo {
+	op, _ := ctx.Value(operationKey{}).(RequestInfo)
+	return RequestInfo{Operation: op.Operation, Cluster: op.Cluster, Plan: op.Plan}
+}
+
+// RequestInfoFromContext returns the RequestInfo describing the attempt
+// that ctx was passed to Middleware for.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// chain wraps doer in the Client's middleware.
+func (c *Client) chain(doer Doer) Doer {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		doer = c.middleware[i](doer)
+	}
+	return doer
+}
+
+// RequestTiming holds the outcome and timings of a single attempt at a
+// request; see [TimingMiddleware].
+type RequestTiming struct {
+	RequestInfo
+
+	Method string
+	// Path is the URL path of the request.
+	Path string
+	// Status is the HTTP status code of the response, or zero if no response
+	// was received.
+	Status int
+	// Duration is the time spent awaiting the response, excluding
+	// RateLimitWait.
+	Duration time.Duration
+	// Err is the decoded error, if any.
+	Err error
+}
+
+// TimingMiddleware returns Middleware which calls record with the timings
+// of each attempt at a request, once it completes. It's suitable for
+// recording metrics.
+func TimingMiddleware(record func(RequestTiming)) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+			resp, timing, err := timeAttempt(ctx, next, req)
+			record(timing)
+			return resp, err
+		})
+	}
+}
+
+// SlogMiddleware returns Middleware which logs each attempt at a request
+// to logger, once it completes. Successful attempts are logged at
+// slog.LevelDebug, and failed attempts at slog.LevelWarn.
+func SlogMiddleware(logger *slog.Logger) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+			resp, t, err := timeAttempt(ctx, next, req)
+
+			level, msg := slog.LevelDebug, "bonsai api request"
+			if err != nil {
+				level, msg = slog.LevelWarn, "bonsai api request failed"
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", t.Method),
+				slog.String("path", t.Path),
+				slog.Int("attempt", t.Attempt),
+				slog.Duration("duration", t.Duration),
+				slog.Duration("rate_limit_wait", t.RateLimitWait),
+			}
+			if t.Operation != "" {
+				attrs = append(attrs, slog.String("operation", t.Operation))
+			}
+			if t.Status != 0 {
+				attrs = append(attrs, slog.Int("status", t.Status))
+			}
+			if resp != nil && resp.httpResponse != nil {
+				if id := resp.Header.Get(HeaderRequestID); id != "" {
+					attrs = append(attrs, slog.String("request_id", id))
+				}
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			}
+
+			logger.LogAttrs(ctx, level, msg, attrs...)
+
+			return resp, err
+		})
+	}
+}
+
+// timeAttempt makes the attempt at req with next, and measures it.
+func timeAttempt(ctx context.Context, next Doer, req *http.Request) (*Response, RequestTiming, error) {
+	info, _ := RequestInfoFromContext(ctx)
+
+	start := time.Now()
+	resp, err := next.Do(ctx, req)
+
+	timing := RequestTiming{
+		RequestInfo: info,
+		Method:      req.Method,
+		Path:        req.URL.Path,
+		Duration:    time.Since(start),
+		Err:         err,
+	}
+	if resp != nil && resp.httpResponse != nil {
+		timing.Status = resp.StatusCode
+	}
+
+	return resp, timing, err
+}
@@ -0,0 +1,115 @@
+package bonsai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+func (s *ClientMockTestSuite) TestClient_WithMiddleware() {
+	const prefix = "/middleware"
+
+	calls := 0
+	s.serveMux.Get(prefix+bonsai.PlanAPIBasePath+"/{slug}", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		if calls == 1 {
+			w.Header().Set(bonsai.HeaderRetryAfter, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, err := fmt.Fprint(w, `{"errors": ["Rate limit exceeded."], "status": 429}`)
+			s.NoError(err, "wrote error response")
+			return
+		}
+		_, err := fmt.Fprint(w, `{"slug": "standard-sm", "name": "Standard Small"}`)
+		s.NoError(err, "wrote plan response")
+	})
+
+	var (
+		order   []string
+		timings []bonsai.RequestTiming
+	)
+	tag := func(name string) bonsai.Middleware {
+		return func(next bonsai.Doer) bonsai.Doer {
+			return bonsai.DoerFunc(func(ctx context.Context, req *http.Request) (*bonsai.Response, error) {
+				order = append(order, name)
+				return next.Do(ctx, req)
+			})
+		}
+	}
+
+	client := bonsai.NewClient(
+		bonsai.WithEndpoint(s.server.URL+prefix),
+		bonsai.WithMiddleware(tag("outer"), tag("inner")),
+		bonsai.WithMiddleware(bonsai.TimingMiddleware(func(t bonsai.RequestTiming) {
+			timings = append(timings, t)
+		})),
+	)
+
+	plan, err := client.Plan.GetBySlug(context.Background(), "standard-sm")
+	s.NoError(err)
+	s.Equal("standard-sm", plan.Slug)
+
+	s.Equal([]string{"outer", "inner", "outer", "inner"}, order, "middleware is applied in order, once per attempt")
+
+	s.Len(timings, 2)
+	for i, t := range timings {
+		s.Equal("Plan.GetBySlug", t.Operation)
+		s.Equal("standard-sm", t.Plan)
+		s.Equal(i+1, t.Attempt)
+		s.Equal(http.MethodGet, t.Method)
+		s.Equal(prefix+bonsai.PlanAPIBasePath+"/standard-sm", t.Path)
+		s.GreaterOrEqual(t.RateLimitWait, time.Duration(0))
+	}
+
+	s.Equal(http.StatusTooManyRequests, timings[0].Status)
+	s.ErrorIs(timings[0].Err, bonsai.ErrHTTPStatusTooManyRequests)
+	s.ErrorAs(timings[0].Err, &bonsai.APIError{}, "middleware sees the decoded error")
+
+	s.Equal(http.StatusOK, timings[1].Status)
+	s.NoError(timings[1].Err)
+}
+
+func (s *ClientMockTestSuite) TestSlogMiddleware() {
+	const prefix = "/slog-middleware"
+
+	s.serveMux.Get(prefix+bonsai.ClusterAPIBasePath+"/{slug}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		w.Header().Set(bonsai.HeaderRequestID, "request-1234")
+		w.WriteHeader(http.StatusNotFound)
+		_, err := fmt.Fprint(w, ResponseErrorHTTPStatusNotFound)
+		s.NoError(err, "wrote error response")
+	})
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := bonsai.NewClient(
+		bonsai.WithEndpoint(s.server.URL+prefix),
+		bonsai.WithMiddleware(bonsai.SlogMiddleware(logger)),
+	)
+
+	_, err := client.Cluster.GetBySlug(context.Background(), "doesnotexist-1234")
+	s.ErrorIs(err, bonsai.ErrHTTPStatusNotFound)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	s.Len(lines, 1)
+
+	record := map[string]any{}
+	s.NoError(json.Unmarshal([]byte(lines[0]), &record))
+	s.Equal("WARN", record["level"])
+	s.Equal("bonsai api request failed", record["msg"])
+	s.Equal("Cluster.GetBySlug", record["operation"])
+	s.Equal(http.MethodGet, record["method"])
+	s.Equal(prefix+bonsai.ClusterAPIBasePath+"/doesnotexist-1234", record["path"])
+	s.InDelta(float64(1), record["attempt"], 0)
+	s.InDelta(float64(http.StatusNotFound), record["status"], 0)
+	s.Equal("request-1234", record["request_id"])
+	s.Contains(record["error"], "Cluster doesnotexist-1234 not found.")
+}
@@ -0,0 +1,227 @@
+// Package otel instruments a bonsai.Client with OpenTelemetry traces and
+// metrics.
+//
+// It's a separate module, such that the core client doesn't depend on
+// OpenTelemetry. Instrumentation is installed as bonsai.Middleware:
+//
+//	client := bonsai.NewClient(
+//		bonsai.WithCredentialPair(credentials),
+//		bonsai.WithMiddleware(otel.Middleware()),
+//	)
+//
+// Each attempt at a request made by the client is recorded as a client
+// span, named after the API operation, such as "Cluster.Create".
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	gootel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer and meter.
+const ScopeName = "github.com/omc/bonsai-api-go/v2/bonsai/otel"
+
+// Attribute keys recorded on spans and metrics.
+const (
+	AttributeOperation   = attribute.Key("bonsai.operation")
+	AttributeClusterSlug = attribute.Key("bonsai.cluster.slug")
+	AttributePlanSlug    = attribute.Key("bonsai.plan.slug")
+	AttributeRetryCount  = attribute.Key("bonsai.retry_count")
+	AttributeRequestID   = attribute.Key("bonsai.request_id")
+
+	AttributeHTTPMethod     = attribute.Key("http.request.method")
+	AttributeHTTPStatusCode = attribute.Key("http.response.status_code")
+	AttributeURLPath        = attribute.Key("url.path")
+)
+
+// Metric names.
+const (
+	MetricRequestDuration  = "bonsai.client.request.duration"
+	MetricRateLimitWait    = "bonsai.client.rate_limit.wait.duration"
+	MetricRateLimited      = "bonsai.client.rate_limited"
+	MetricRequestsInFlight = "bonsai.client.requests.in_flight"
+)
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider configures the TracerProvider spans are created with.
+// The global TracerProvider is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider configures the MeterProvider metrics are recorded with.
+// The global MeterProvider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+type instruments struct {
+	tracer trace.Tracer
+
+	duration    metric.Float64Histogram
+	wait        metric.Float64Histogram
+	rateLimited metric.Int64Counter
+	inFlight    metric.Int64UpDownCounter
+}
+
+// Middleware returns bonsai.Middleware which records a span, and metrics,
+// for each attempt at a request.
+//
+// Spans are attributed with the operation, the cluster and plan slugs where
+// known, the HTTP status, and the number of retries preceding the attempt.
+// The following metrics are recorded:
+//
+//   - bonsai.client.request.duration: latency of each attempt, in seconds
+//   - bonsai.client.rate_limit.wait.duration: time spent waiting on the
+//     client's rate limiters, in seconds
+//   - bonsai.client.rate_limited: count of 429 Too Many Requests responses
+//   - bonsai.client.requests.in_flight: attempts awaiting a response
+func Middleware(opts ...Option) bonsai.Middleware {
+	cfg := config{
+		tracerProvider: gootel.GetTracerProvider(),
+		meterProvider:  gootel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	inst, err := newInstruments(cfg)
+	if err != nil {
+		gootel.Handle(err)
+	}
+
+	return func(next bonsai.Doer) bonsai.Doer {
+		return bonsai.DoerFunc(func(ctx context.Context, req *http.Request) (*bonsai.Response, error) {
+			return inst.do(ctx, next, req)
+		})
+	}
+}
+
+func newInstruments(cfg config) (*instruments, error) {
+	meter := cfg.meterProvider.Meter(ScopeName, metric.WithInstrumentationVersion(bonsai.Version))
+	inst := &instruments{
+		tracer: cfg.tracerProvider.Tracer(ScopeName, trace.WithInstrumentationVersion(bonsai.Version)),
+	}
+
+	var err, errs error
+	inst.duration, err = meter.Float64Histogram(
+		MetricRequestDuration,
+		metric.WithDescription("Duration of requests made to the Bonsai API."),
+		metric.WithUnit("s"),
+	)
+	errs = errors.Join(errs, err)
+
+	inst.wait, err = meter.Float64Histogram(
+		MetricRateLimitWait,
+		metric.WithDescription("Time requests spent waiting on client-side rate limits."),
+		metric.WithUnit("s"),
+	)
+	errs = errors.Join(errs, err)
+
+	inst.rateLimited, err = meter.Int64Counter(
+		MetricRateLimited,
+		metric.WithDescription("Number of requests rejected by the Bonsai API as rate limited."),
+		metric.WithUnit("{request}"),
+	)
+	errs = errors.Join(errs, err)
+
+	inst.inFlight, err = meter.Int64UpDownCounter(
+		MetricRequestsInFlight,
+		metric.WithDescription("Number of requests awaiting a response from the Bonsai API."),
+		metric.WithUnit("{request}"),
+	)
+	errs = errors.Join(errs, err)
+
+	return inst, errs
+}
+
+func (inst *instruments) do(ctx context.Context, next bonsai.Doer, req *http.Request) (*bonsai.Response, error) {
+	info, _ := bonsai.RequestInfoFromContext(ctx)
+
+	name := info.Operation
+	if name == "" {
+		name = req.Method
+	}
+
+	attrs := []attribute.KeyValue{
+		AttributeHTTPMethod.String(req.Method),
+	}
+	if info.Operation != "" {
+		attrs = append(attrs, AttributeOperation.String(info.Operation))
+	}
+	metricOpt := metric.WithAttributeSet(attribute.NewSet(attrs...))
+
+	spanAttrs := slices.Concat(attrs, []attribute.KeyValue{
+		AttributeURLPath.String(req.URL.Path),
+		AttributeRetryCount.Int(max(info.Attempt-1, 0)),
+	})
+	if info.Cluster != "" {
+		spanAttrs = append(spanAttrs, AttributeClusterSlug.String(info.Cluster))
+	}
+	if info.Plan != "" {
+		spanAttrs = append(spanAttrs, AttributePlanSlug.String(info.Plan))
+	}
+
+	ctx, span := inst.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...),
+	)
+	defer span.End()
+
+	inst.wait.Record(ctx, info.RateLimitWait.Seconds(), metricOpt)
+	inst.inFlight.Add(ctx, 1, metricOpt)
+
+	start := time.Now()
+	resp, err := next.Do(ctx, req.WithContext(ctx))
+	elapsed := time.Since(start)
+
+	inst.inFlight.Add(ctx, -1, metricOpt)
+
+	status := 0
+	if resp.HTTPResponse() != nil {
+		status = resp.StatusCode
+		span.SetAttributes(AttributeHTTPStatusCode.Int(status))
+		if id := resp.Header.Get(bonsai.HeaderRequestID); id != "" {
+			span.SetAttributes(AttributeRequestID.String(id))
+		}
+	}
+
+	durationAttrs := slices.Clip(attrs)
+	if status != 0 {
+		durationAttrs = append(durationAttrs, AttributeHTTPStatusCode.Int(status))
+	}
+	inst.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributeSet(attribute.NewSet(durationAttrs...)))
+
+	if status == http.StatusTooManyRequests {
+		inst.rateLimited.Add(ctx, 1, metricOpt)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return resp, err
+}
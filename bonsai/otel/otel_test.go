@@ -0,0 +1,175 @@
+package otel_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/bonsaitest"
+	"github.com/omc/bonsai-api-go/v2/bonsai/otel"
+)
+
+type OtelTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all HTTP request tests
+	suite.Suite
+
+	// server is the fake API server, recreated for each test
+	server *bonsaitest.Server
+	// client is wired to make requests against server, and instrumented
+	client *bonsai.Client
+	// spans records the spans ended by client requests
+	spans *tracetest.SpanRecorder
+	// metrics is read from to collect the metrics recorded by client requests
+	metrics *sdkmetric.ManualReader
+}
+
+func (s *OtelTestSuite) SetupTest() {
+	s.spans = tracetest.NewSpanRecorder()
+	s.metrics = sdkmetric.NewManualReader()
+
+	s.server = bonsaitest.NewServer(bonsaitest.WithFixtures(bonsaitest.Fixtures{
+		Clusters: []bonsai.Cluster{
+			{
+				Slug:  "existing-cluster-1234567890",
+				Name:  "existing_cluster",
+				Plan:  bonsai.Plan{Slug: "standard-sm"},
+				Space: bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+				State: bonsai.ClusterStateProvisioned,
+			},
+		},
+		Plans:    bonsaitest.DefaultFixtures().Plans,
+		Spaces:   bonsaitest.DefaultFixtures().Spaces,
+		Releases: bonsaitest.DefaultFixtures().Releases,
+	}))
+	s.client = s.server.Client(bonsai.WithMiddleware(otel.Middleware(
+		otel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.spans))),
+		otel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(s.metrics))),
+	)))
+
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func (s *OtelTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestOtelTestSuite(t *testing.T) {
+	suite.Run(t, new(OtelTestSuite))
+}
+
+// collect returns the metrics recorded so far, by name.
+func (s *OtelTestSuite) collect() map[string]metricdata.Metrics {
+	rm := metricdata.ResourceMetrics{}
+	s.NoError(s.metrics.Collect(context.Background(), &rm))
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		s.Equal(otel.ScopeName, sm.Scope.Name)
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func (s *OtelTestSuite) TestSpans() {
+	s.server.FailNext(http.MethodPut, bonsai.ClusterAPIBasePath+"/existing-cluster-1234567890", http.StatusTooManyRequests)
+
+	_, err := s.client.Cluster.Update(context.Background(), "existing-cluster-1234567890", bonsai.ClusterUpdateOpts{
+		Name: "renamed",
+		Plan: "business-sm",
+	})
+	s.NoError(err)
+
+	spans := s.spans.Ended()
+	s.Len(spans, 2, "one span per attempt")
+
+	for i, span := range spans {
+		s.Equal("Cluster.Update", span.Name())
+		s.Equal(trace.SpanKindClient, span.SpanKind())
+
+		attrs := spanAttributes(span)
+		s.Equal("Cluster.Update", attrs[otel.AttributeOperation].AsString())
+		s.Equal("existing-cluster-1234567890", attrs[otel.AttributeClusterSlug].AsString())
+		s.Equal("business-sm", attrs[otel.AttributePlanSlug].AsString())
+		s.Equal(http.MethodPut, attrs[otel.AttributeHTTPMethod].AsString())
+		s.Equal(bonsai.ClusterAPIBasePath+"/existing-cluster-1234567890", attrs[otel.AttributeURLPath].AsString())
+		s.Equal(int64(i), attrs[otel.AttributeRetryCount].AsInt64())
+	}
+
+	s.Equal(int64(http.StatusTooManyRequests), spanAttributes(spans[0])[otel.AttributeHTTPStatusCode].AsInt64())
+	s.Equal(codes.Error, spans[0].Status().Code)
+	s.NotEmpty(spans[0].Events(), "error is recorded on the span")
+
+	s.Equal(int64(http.StatusAccepted), spanAttributes(spans[1])[otel.AttributeHTTPStatusCode].AsInt64())
+	s.Equal(codes.Unset, spans[1].Status().Code)
+}
+
+func (s *OtelTestSuite) TestMetrics() {
+	s.server.FailNext(http.MethodGet, bonsai.PlanAPIBasePath, http.StatusTooManyRequests)
+
+	_, err := s.client.Plan.All(context.Background())
+	s.NoError(err)
+	_, err = s.client.Cluster.GetBySlug(context.Background(), "existing-cluster-1234567890")
+	s.NoError(err)
+
+	metrics := s.collect()
+
+	duration, ok := metrics[otel.MetricRequestDuration].Data.(metricdata.Histogram[float64])
+	s.True(ok, "request duration is a histogram")
+	counts := map[string]uint64{}
+	for _, dp := range duration.DataPoints {
+		op, _ := dp.Attributes.Value(otel.AttributeOperation)
+		status, _ := dp.Attributes.Value(otel.AttributeHTTPStatusCode)
+		counts[op.AsString()+" "+status.Emit()] += dp.Count
+	}
+	s.Equal(map[string]uint64{
+		"Plan.List 429":         1,
+		"Plan.List 200":         1,
+		"Cluster.GetBySlug 200": 1,
+	}, counts)
+
+	wait, ok := metrics[otel.MetricRateLimitWait].Data.(metricdata.Histogram[float64])
+	s.True(ok, "rate limit wait is a histogram")
+	var waits uint64
+	for _, dp := range wait.DataPoints {
+		waits += dp.Count
+	}
+	s.Equal(uint64(3), waits)
+
+	rateLimited, ok := metrics[otel.MetricRateLimited].Data.(metricdata.Sum[int64])
+	s.True(ok, "rate limited is a sum")
+	s.Len(rateLimited.DataPoints, 1)
+	s.Equal(int64(1), rateLimited.DataPoints[0].Value)
+	op, _ := rateLimited.DataPoints[0].Attributes.Value(otel.AttributeOperation)
+	s.Equal("Plan.List", op.AsString())
+
+	inFlight, ok := metrics[otel.MetricRequestsInFlight].Data.(metricdata.Sum[int64])
+	s.True(ok, "in-flight requests is a sum")
+	s.False(inFlight.IsMonotonic)
+	for _, dp := range inFlight.DataPoints {
+		s.Equal(int64(0), dp.Value, "no requests remain in flight")
+	}
+}
@@ -0,0 +1,165 @@
+package bonsai
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// PageOpts specifies which page of a paginated list endpoint to start from,
+// and how many results each page should hold.
+//
+// ref: https://bonsai.io/docs/api-result-pagination
+type PageOpts struct {
+	// Page number to start from, starting at 1. If zero, the API's default
+	// (the first page) is used.
+	Page int
+	// Size of each page, with a max of 100. If zero, the API's default is used.
+	Size int
+}
+
+func (o PageOpts) listOpts() listOpts {
+	return listOpts(o)
+}
+
+// pageFetcher retrieves a single page of results.
+type pageFetcher[T any] func(ctx context.Context, opt listOpts) ([]T, *Response, error)
+
+// Pager iterates over the pages of a paginated list endpoint, fetching
+// each page on demand. Pagers are created by the Iter method of each
+// resource client, for example [ClusterClient.Iter].
+//
+// A Pager is not safe for concurrent use.
+//
+//	pager := client.Cluster.Iter(bonsai.ClusterAllOpts{}, bonsai.PageOpts{})
+//	for pager.Next(ctx) {
+//		for _, cluster := range pager.Page() {
+//			...
+//		}
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+type Pager[T any] struct {
+	client *Client
+	fetch  pageFetcher[T]
+
+	opt  listOpts
+	done bool
+
+	page []T
+	resp *Response
+	err  error
+}
+
+func newPager[T any](client *Client, opt PageOpts, fetch pageFetcher[T]) *Pager[T] {
+	return &Pager[T]{
+		client: client,
+		fetch:  fetch,
+		opt:    opt.listOpts(),
+	}
+}
+
+// Next fetches the next page of results, returning false once all pages
+// have been fetched, or an error occurred; see [Pager.Err].
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+
+	if err := ctx.Err(); err != nil {
+		p.fail(err)
+		return false
+	}
+
+	page, resp, err := p.fetch(ctx, p.opt)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+
+	p.page, p.resp = page, resp
+	p.done, p.opt = nextPage(p.opt, resp, len(page))
+
+	return true
+}
+
+// Page returns the results of the page most recently fetched by Next.
+func (p *Pager[T]) Page() []T {
+	return p.page
+}
+
+// Response returns the Response of the page most recently fetched by Next,
+// which holds the pagination details reported by the API.
+func (p *Pager[T]) Response() *Response {
+	return p.resp
+}
+
+// PageOpts returns the options which the next call to Next will fetch
+// with, such that iteration may be resumed later, by another Pager.
+func (p *Pager[T]) PageOpts() PageOpts {
+	return PageOpts(p.opt)
+}
+
+// Err returns the error, if any, which stopped iteration.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All fetches every remaining page, and returns all of their results.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	allResults := make([]T, 0, defaultListResultSize)
+
+	if p.done {
+		return allResults, p.err
+	}
+
+	err := p.client.all(ctx, p.opt, func(opt listOpts) (*Response, error) {
+		page, resp, err := p.fetch(ctx, opt)
+		if err != nil {
+			return resp, fmt.Errorf("client.list failed: %w", err)
+		}
+
+		allResults = append(allResults, page...)
+		p.page, p.resp = page, resp
+		p.done, p.opt = nextPage(opt, resp, len(page))
+		if p.done {
+			resp.MarkPaginationComplete()
+		}
+		return resp, nil
+	})
+
+	if err != nil {
+		p.fail(err)
+		return allResults, fmt.Errorf("client.all failed: %w", err)
+	}
+
+	p.done = true
+	return allResults, nil
+}
+
+func (p *Pager[T]) fail(err error) {
+	p.err = err
+	p.done = true
+}
+
+// nextPage determines, from the response to a request for opt holding count
+// results, whether all pages have been fetched, and if not, which options
+// the next page should be fetched with.
+func nextPage(opt listOpts, resp *Response, count int) (bool, listOpts) {
+	if resp == nil || reflect.ValueOf(resp.PaginatedResponse).IsZero() || resp.PageNumber <= 0 || count == 0 {
+		return true, opt
+	}
+
+	// The response may be shorter than the page size, so rely on the
+	// number of results received when counting those delivered so far.
+	delivered := (resp.PageNumber-1)*resp.PageSize + count
+	if delivered >= resp.TotalRecords {
+		return true, opt
+	}
+
+	return false, listOpts{
+		Page: resp.PageNumber + 1,
+		Size: resp.PageSize,
+	}
+}
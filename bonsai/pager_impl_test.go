@@ -0,0 +1,53 @@
+package bonsai
+
+func (s *ClientImplTestSuite) TestNextPage() {
+	testCases := []struct {
+		name       string
+		opt        listOpts
+		pagination PaginatedResponse
+		count      int
+		expectDone bool
+		expectOpt  listOpts
+	}{
+		{
+			name:       "without pagination details",
+			opt:        listOpts{},
+			pagination: PaginatedResponse{},
+			count:      10,
+			expectDone: true,
+			expectOpt:  listOpts{},
+		},
+		{
+			name:       "more pages remain",
+			opt:        listOpts{Page: 1, Size: 10},
+			pagination: PaginatedResponse{PageNumber: 1, PageSize: 10, TotalRecords: 25},
+			count:      10,
+			expectDone: false,
+			expectOpt:  listOpts{Page: 2, Size: 10},
+		},
+		{
+			name:       "last, partial, page",
+			opt:        listOpts{Page: 3, Size: 10},
+			pagination: PaginatedResponse{PageNumber: 3, PageSize: 10, TotalRecords: 25},
+			count:      5,
+			expectDone: true,
+			expectOpt:  listOpts{Page: 3, Size: 10},
+		},
+		{
+			name:       "empty page",
+			opt:        listOpts{Page: 4, Size: 10},
+			pagination: PaginatedResponse{PageNumber: 4, PageSize: 10, TotalRecords: 50},
+			count:      0,
+			expectDone: true,
+			expectOpt:  listOpts{Page: 4, Size: 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			done, opt := nextPage(tc.opt, &Response{PaginatedResponse: tc.pagination}, tc.count)
+			s.Equal(tc.expectDone, done)
+			s.Equal(tc.expectOpt, opt)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package bonsai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// servePaginatedReleases serves total releases from the releases endpoint,
+// paginated per the page and size query parameters, and returns a pointer
+// to the count of requests served.
+func (s *ClientMockTestSuite) servePaginatedReleases(total int) *int {
+	calls := 0
+
+	s.serveMux.Get(bonsai.ReleaseAPIBasePath, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		page, size := 1, total
+		if v := r.URL.Query().Get("page"); v != "" {
+			page, _ = strconv.Atoi(v)
+		}
+		if v := r.URL.Query().Get("size"); v != "" {
+			size, _ = strconv.Atoi(v)
+		}
+
+		releases := make([]bonsai.Release, 0, size)
+		for i := (page - 1) * size; i < min(page*size, total); i++ {
+			releases = append(releases, bonsai.Release{Slug: fmt.Sprintf("release-%d", i)})
+		}
+
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		err := json.NewEncoder(w).Encode(map[string]any{
+			"releases": releases,
+			"pagination": bonsai.PaginatedResponse{
+				PageNumber:   page,
+				PageSize:     size,
+				TotalRecords: total,
+			},
+		})
+		s.NoError(err, "encode paginated releases")
+	})
+
+	return &calls
+}
+
+func (s *ClientMockTestSuite) TestPager() {
+	const total = 5
+
+	slugs := func(releases []bonsai.Release) []string {
+		result := make([]string, len(releases))
+		for i, release := range releases {
+			result[i] = release.Slug
+		}
+		return result
+	}
+
+	s.Run("iterates page by page", func() {
+		calls := s.servePaginatedReleases(total)
+
+		pager := s.client.Release.Iter(bonsai.PageOpts{Size: 2})
+		pages := make([][]string, 0, 3)
+		for pager.Next(context.Background()) {
+			pages = append(pages, slugs(pager.Page()))
+			s.Equal(total, pager.Response().TotalRecords)
+		}
+		s.NoError(pager.Err())
+
+		s.Equal([][]string{
+			{"release-0", "release-1"},
+			{"release-2", "release-3"},
+			{"release-4"},
+		}, pages)
+		s.Equal(3, *calls)
+		s.False(pager.Next(context.Background()), "exhausted pager stays exhausted")
+	})
+
+	s.Run("stops early and resumes", func() {
+		calls := s.servePaginatedReleases(total)
+
+		pager := s.client.Release.Iter(bonsai.PageOpts{Size: 2})
+		s.True(pager.Next(context.Background()))
+		s.Equal([]string{"release-0", "release-1"}, slugs(pager.Page()))
+		s.Equal(bonsai.PageOpts{Page: 2, Size: 2}, pager.PageOpts())
+
+		resumed := s.client.Release.Iter(pager.PageOpts())
+		rest, err := resumed.All(context.Background())
+		s.NoError(err)
+		s.Equal([]string{"release-2", "release-3", "release-4"}, slugs(rest))
+		s.Equal(3, *calls)
+	})
+
+	s.Run("starts from a given page", func() {
+		s.servePaginatedReleases(total)
+
+		releases, err := s.client.Release.Iter(bonsai.PageOpts{Page: 3, Size: 2}).All(context.Background())
+		s.NoError(err)
+		s.Equal([]string{"release-4"}, slugs(releases))
+	})
+
+	s.Run("all collects every page", func() {
+		calls := s.servePaginatedReleases(total)
+
+		releases, err := s.client.Release.Iter(bonsai.PageOpts{Page: 1, Size: 1}).All(context.Background())
+		s.NoError(err)
+		s.Len(releases, total)
+		s.Equal(total, *calls)
+	})
+
+	s.Run("stops on canceled context", func() {
+		s.servePaginatedReleases(total)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		pager := s.client.Release.Iter(bonsai.PageOpts{Size: 2})
+		s.False(pager.Next(ctx))
+		s.ErrorIs(pager.Err(), context.Canceled)
+	})
+}
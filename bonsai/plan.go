@@ -3,11 +3,13 @@ package bonsai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"reflect"
+	"sync"
 )
 
 const (
@@ -168,8 +170,6 @@ func (o planListOptions) values() url.Values {
 
 // list returns a list of Plans for the page specified,
 // by performing a GET request against [spaceAPIBasePath].
-//
-// Note: Pagination is not currently supported.
 func (c *PlanClient) list(ctx context.Context, opt planListOptions) ([]Plan, *Response, error) {
 	var (
 		req    *http.Request
@@ -199,7 +199,7 @@ func (c *PlanClient) list(ctx context.Context, opt planListOptions) ([]Plan, *Re
 		return results, nil, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Plan.List"}), req)
 	if err != nil {
 		return results, resp, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -214,36 +214,17 @@ func (c *PlanClient) list(ctx context.Context, opt planListOptions) ([]Plan, *Re
 	return results, resp, nil
 }
 
-// All lists all Plans from the Plans API.
-func (c *PlanClient) All(ctx context.Context) ([]Plan, error) {
-	var (
-		err  error
-		resp *Response
-	)
-
-	allResults := make([]Plan, 0, defaultListResultSize)
-	// No pagination support as yet, but support it for future use
-
-	err = c.all(ctx, newEmptyListOpts(), func(opt listOpts) (*Response, error) {
-		var listResults []Plan
-
-		listResults, resp, err = c.list(ctx, planListOptions{listOpts: opt})
-		if err != nil {
-			return resp, fmt.Errorf("client.list failed: %w", err)
-		}
-
-		allResults = append(allResults, listResults...)
-		if len(allResults) >= resp.TotalRecords {
-			resp.MarkPaginationComplete()
-		}
-		return resp, err
+// Iter returns a Pager over the Plans from the Plans API, starting from the
+// page specified by page.
+func (c *PlanClient) Iter(page PageOpts) *Pager[Plan] {
+	return newPager(c.Client, page, func(ctx context.Context, o listOpts) ([]Plan, *Response, error) {
+		return c.list(ctx, planListOptions{listOpts: o})
 	})
+}
 
-	if err != nil {
-		return allResults, fmt.Errorf("client.all failed: %w", err)
-	}
-
-	return allResults, err
+// All lists all Plans from the Plans API.
+func (c *PlanClient) All(ctx context.Context) ([]Plan, error) {
+	return c.Iter(PageOpts{}).All(ctx)
 }
 
 // GetBySlug gets a Plan from the Plans API by its slug.
@@ -270,7 +251,7 @@ func (c *PlanClient) GetBySlug(ctx context.Context, slug string) (Plan, error) {
 		return result, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Plan.GetBySlug", Plan: slug}), req)
 	if err != nil {
 		return result, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -281,3 +262,215 @@ func (c *PlanClient) GetBySlug(ctx context.Context, slug string) (Plan, error) {
 
 	return result, nil
 }
+
+// maxExpandConcurrency bounds the number of concurrent requests made to
+// resolve the Releases and Spaces of expanded Plans.
+const maxExpandConcurrency = 4
+
+// GetBySlugExpanded gets a Plan from the Plans API by its slug, resolving
+// its AvailableReleases and AvailableSpaces into full Releases and Spaces;
+// see AllExpanded.
+//
+// Rather than listing all Releases and Spaces, only those available for the
+// Plan are fetched, individually.
+func (c *PlanClient) GetBySlugExpanded(ctx context.Context, slug string) (Plan, error) {
+	plan, err := c.GetBySlug(ctx, slug)
+	if err != nil {
+		return plan, err
+	}
+
+	plans := []Plan{plan}
+	if err = newEmptyPlanExpander(c.Client).expand(ctx, plans); err != nil {
+		return plan, err
+	}
+	return plans[0], nil
+}
+
+// AllExpanded lists all Plans from the Plans API, resolving their
+// AvailableReleases and AvailableSpaces, which the API returns as slugs and
+// paths alone, into full Releases and Spaces.
+//
+// Releases and Spaces are listed concurrently, and each is fetched at most
+// once, however many Plans it's available for. Those missing from the lists
+// are fetched individually; any the API doesn't find are left as returned.
+// Configure the Client with a Cache, see WithCache, to share the results
+// between calls.
+func (c *PlanClient) AllExpanded(ctx context.Context) ([]Plan, error) {
+	var (
+		plans    []Plan
+		expander *planExpander
+		plansErr error
+		err      error
+		wg       sync.WaitGroup
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		plans, plansErr = c.All(ctx)
+	}()
+	expander, err = newPlanExpander(ctx, c.Client)
+	wg.Wait()
+
+	if err = errors.Join(plansErr, err); err != nil {
+		return plans, err
+	}
+
+	if err = expander.expand(ctx, plans); err != nil {
+		return plans, err
+	}
+	return plans, nil
+}
+
+// planExpander resolves the Release and Space stubs of Plans.
+type planExpander struct {
+	client *Client
+
+	mu       sync.Mutex
+	releases map[string]Release
+	spaces   map[string]Space
+}
+
+// newEmptyPlanExpander returns a planExpander which knows of no Releases or
+// Spaces, such that each is fetched individually.
+func newEmptyPlanExpander(client *Client) *planExpander {
+	return &planExpander{
+		client:   client,
+		releases: map[string]Release{},
+		spaces:   map[string]Space{},
+	}
+}
+
+// newPlanExpander returns a planExpander, seeded concurrently with all
+// Releases and Spaces.
+func newPlanExpander(ctx context.Context, client *Client) (*planExpander, error) {
+	var (
+		releases               []Release
+		spaces                 []Space
+		releasesErr, spacesErr error
+		wg                     sync.WaitGroup
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		releases, releasesErr = client.Release.All(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		spaces, spacesErr = client.Space.All(ctx)
+	}()
+	wg.Wait()
+
+	if err := errors.Join(releasesErr, spacesErr); err != nil {
+		return nil, fmt.Errorf("listing releases and spaces to expand plans: %w", err)
+	}
+
+	e := &planExpander{
+		client:   client,
+		releases: make(map[string]Release, len(releases)),
+		spaces:   make(map[string]Space, len(spaces)),
+	}
+	for _, r := range releases {
+		e.releases[r.Slug] = r
+	}
+	for _, s := range spaces {
+		e.spaces[s.Path] = s
+	}
+
+	return e, nil
+}
+
+// expand replaces the Release and Space stubs of plans, in place, fetching
+// any not yet known.
+func (e *planExpander) expand(ctx context.Context, plans []Plan) error {
+	if err := e.fetchMissing(ctx, plans); err != nil {
+		return err
+	}
+
+	for i := range plans {
+		for j, r := range plans[i].AvailableReleases {
+			if release, ok := e.releases[r.Slug]; ok {
+				plans[i].AvailableReleases[j] = release
+			}
+		}
+		for j, s := range plans[i].AvailableSpaces {
+			if space, ok := e.spaces[s.Path]; ok {
+				plans[i].AvailableSpaces[j] = space
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetchMissing fetches the Releases and Spaces of plans which weren't
+// listed, with bounded concurrency.
+func (e *planExpander) fetchMissing(ctx context.Context, plans []Plan) error {
+	var fetches []func(context.Context) error
+
+	seen := map[string]bool{}
+	for _, plan := range plans {
+		for _, r := range plan.AvailableReleases {
+			if _, ok := e.releases[r.Slug]; ok || seen["release:"+r.Slug] {
+				continue
+			}
+			seen["release:"+r.Slug] = true
+			slug := r.Slug
+			fetches = append(fetches, func(ctx context.Context) error {
+				release, err := e.client.Release.GetBySlug(ctx, slug)
+				if err != nil {
+					return err
+				}
+				e.mu.Lock()
+				defer e.mu.Unlock()
+				e.releases[slug] = release
+				return nil
+			})
+		}
+		for _, s := range plan.AvailableSpaces {
+			if _, ok := e.spaces[s.Path]; ok || seen["space:"+s.Path] {
+				continue
+			}
+			seen["space:"+s.Path] = true
+			spacePath := s.Path
+			fetches = append(fetches, func(ctx context.Context) error {
+				space, err := e.client.Space.GetByPath(ctx, spacePath)
+				if err != nil {
+					return err
+				}
+				e.mu.Lock()
+				defer e.mu.Unlock()
+				e.spaces[spacePath] = space
+				return nil
+			})
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, maxExpandConcurrency)
+	)
+	for _, fetch := range fetches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			// Plans may reference Releases and Spaces which no longer exist;
+			// leave those as returned.
+			if err := fetch(ctx); err != nil && !errors.Is(err, ErrHTTPStatusNotFound) {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("fetching releases and spaces to expand plans: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
 
 	"github.com/omc/bonsai-api-go/v2/bonsai"
 )
@@ -177,6 +180,153 @@ func (s *ClientMockTestSuite) TestPlanClient_GetByPath() {
 	s.Equal(expect, resultResp, "expected struct matches unmarshaled result")
 }
 
+func (s *ClientMockTestSuite) TestPlanClient_Expanded() {
+	const prefix = "/plans-expanded"
+	ctx := context.Background()
+
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+	)
+	count := func(r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests[r.URL.Path[len(prefix):]]++
+	}
+	respond := func(w http.ResponseWriter, body string) {
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		_, err := fmt.Fprint(w, body)
+		s.NoError(err, "wrote response")
+	}
+
+	const (
+		sandbox = `{
+			"slug": "sandbox-aws-us-east-1",
+			"name": "Sandbox",
+			"available_releases": ["elasticsearch-7.2.0", "opensearch-2.6.0-mt"],
+			"available_spaces": ["omc/bonsai/us-east-1/common"]
+		}`
+		standard = `{
+			"slug": "standard-sm",
+			"name": "Standard Small",
+			"available_releases": ["elasticsearch-7.2.0", "elasticsearch-5.6.16"],
+			"available_spaces": ["omc/bonsai/us-east-1/common", "omc/bonsai/eu-west-1/common"]
+		}`
+	)
+
+	s.serveMux.Get(prefix+bonsai.PlanAPIBasePath, func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		respond(w, `{"plans": [`+sandbox+`,`+standard+`]}`)
+	})
+	s.serveMux.Get(prefix+bonsai.PlanAPIBasePath+"/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		respond(w, standard)
+	})
+	s.serveMux.Get(prefix+bonsai.ReleaseAPIBasePath, func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		respond(w, `{"releases": [
+			{"slug": "elasticsearch-7.2.0", "name": "Elasticsearch 7.2.0", "service_type": "elasticsearch", "version": "7.2.0"},
+			{"slug": "opensearch-2.6.0-mt", "name": "OpenSearch 2.6.0", "service_type": "opensearch", "version": "2.6.0"}
+		]}`)
+	})
+	s.serveMux.Get(prefix+bonsai.ReleaseAPIBasePath+"/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		if chi.URLParam(r, "slug") == "elasticsearch-7.2.0" {
+			respond(w, `{
+				"slug": "elasticsearch-7.2.0",
+				"name": "Elasticsearch 7.2.0",
+				"service_type": "elasticsearch",
+				"version": "7.2.0"
+			}`)
+			return
+		}
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		w.WriteHeader(http.StatusNotFound)
+		_, err := fmt.Fprintf(w, `{"errors": ["Release %s not found."], "status": 404}`, chi.URLParam(r, "slug"))
+		s.NoError(err, "wrote error response")
+	})
+	s.serveMux.Get(prefix+bonsai.SpaceAPIBasePath, func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		respond(w, `{"spaces": [
+			{"path": "omc/bonsai/us-east-1/common", "region": "aws-us-east-1", "cloud": {"provider": "aws", "region": "aws-us-east-1"}}
+		]}`)
+	})
+	s.serveMux.Get(prefix+bonsai.SpaceAPIBasePath+"/*", func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		path := chi.URLParam(r, "*")
+		region := map[string]string{
+			"omc/bonsai/us-east-1/common": "aws-us-east-1",
+			"omc/bonsai/eu-west-1/common": "aws-eu-west-1",
+		}[path]
+		respond(w, `{"path": "`+path+`", "region": "`+region+`", "cloud": {"provider": "aws", "region": "`+region+`"}}`)
+	})
+
+	client := bonsai.NewClient(bonsai.WithEndpoint(s.server.URL + prefix))
+
+	plans, err := client.Plan.AllExpanded(ctx)
+	s.NoError(err)
+	s.Len(plans, 2)
+
+	s.Equal(bonsai.Release{
+		Slug:        "elasticsearch-7.2.0",
+		Name:        "Elasticsearch 7.2.0",
+		ServiceType: "elasticsearch",
+		Version:     "7.2.0",
+	}, plans[0].AvailableReleases[0])
+	s.Equal("opensearch", plans[0].AvailableReleases[1].ServiceType)
+	s.Equal("aws-us-east-1", plans[0].AvailableSpaces[0].Cloud.Region)
+
+	s.Equal(bonsai.Release{Slug: "elasticsearch-5.6.16"}, plans[1].AvailableReleases[1], "missing releases are left as returned")
+	s.Equal("aws-eu-west-1", plans[1].AvailableSpaces[1].Cloud.Region, "unlisted spaces are fetched individually")
+
+	s.Equal(map[string]int{
+		bonsai.PlanAPIBasePath:                                   1,
+		bonsai.ReleaseAPIBasePath:                                1,
+		bonsai.ReleaseAPIBasePath + "/elasticsearch-5.6.16":      1,
+		bonsai.SpaceAPIBasePath:                                  1,
+		bonsai.SpaceAPIBasePath + "/omc/bonsai/eu-west-1/common": 1,
+	}, requests, "each release and space is fetched once")
+
+	clear(requests)
+	plan, err := client.Plan.GetBySlugExpanded(ctx, "standard-sm")
+	s.NoError(err)
+	s.Equal("Standard Small", plan.Name)
+	s.Equal("7.2.0", plan.AvailableReleases[0].Version)
+	s.Equal(bonsai.Release{Slug: "elasticsearch-5.6.16"}, plan.AvailableReleases[1])
+	s.Equal("aws-us-east-1", plan.AvailableSpaces[0].Cloud.Region)
+	s.Equal("aws-eu-west-1", plan.AvailableSpaces[1].Cloud.Region)
+
+	s.Equal(map[string]int{
+		bonsai.PlanAPIBasePath + "/standard-sm":                  1,
+		bonsai.ReleaseAPIBasePath + "/elasticsearch-7.2.0":       1,
+		bonsai.ReleaseAPIBasePath + "/elasticsearch-5.6.16":      1,
+		bonsai.SpaceAPIBasePath + "/omc/bonsai/us-east-1/common": 1,
+		bonsai.SpaceAPIBasePath + "/omc/bonsai/eu-west-1/common": 1,
+	}, requests, "only the plan's releases and spaces are fetched")
+}
+
+func (s *ClientMockTestSuite) TestPlanClient_ExpandedErrors() {
+	const prefix = "/plans-expanded-errors"
+
+	s.serveMux.Get(prefix+bonsai.PlanAPIBasePath+"/{slug}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		_, err := fmt.Fprint(w,
+			`{"slug": "standard-sm", "available_releases": ["elasticsearch-7.2.0"], "available_spaces": []}`)
+		s.NoError(err, "wrote plan response")
+	})
+	s.serveMux.Get(prefix+bonsai.ReleaseAPIBasePath+"/{slug}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		w.WriteHeader(http.StatusForbidden)
+		_, err := fmt.Fprint(w, `{"errors": ["Forbidden."], "status": 403}`)
+		s.NoError(err, "wrote error response")
+	})
+
+	client := bonsai.NewClient(bonsai.WithEndpoint(s.server.URL + prefix))
+
+	_, err := client.Plan.GetBySlugExpanded(context.Background(), "standard-sm")
+	s.ErrorIs(err, bonsai.ErrHTTPStatusForbidden)
+}
+
 // VCR Tests.
 func (s *ClientVCRTestSuite) TestPlanClient_All() {
 	ctx := context.Background()
@@ -0,0 +1,448 @@
+package bonsai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultProfile is the name of the profile used when none is specified.
+const DefaultProfile = "default"
+
+// Profile keys holding credentials.
+const (
+	profileKeyAccessKey   = "access_key"
+	profileKeyAccessToken = "access_token"
+)
+
+// profiles holds the settings of each named profile in a profiles file.
+type profiles map[string]map[string]string
+
+// parseProfiles parses a profiles file, which may hold either JSON or INI.
+//
+// JSON files hold an object of profiles, by name:
+//
+//	{"default": {"access_key": "...", "access_token": "..."}}
+//
+// INI files hold a section per profile:
+//
+//	[default]
+//	access_key = ...
+//	access_token = ...
+//
+// Files are parsed as JSON if name has a ".json" extension, or their first
+// non-space character is "{", and as INI otherwise.
+func parseProfiles(name string, data []byte) (profiles, error) {
+	trimmed := bytes.TrimSpace(data)
+	if strings.EqualFold(filepath.Ext(name), ".json") || bytes.HasPrefix(trimmed, []byte("{")) {
+		p, err := parseJSONProfiles(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s as json: %w", name, err)
+		}
+		return p, nil
+	}
+
+	p, err := parseINI(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s as ini: %w", name, err)
+	}
+	return p, nil
+}
+
+// parseJSONProfiles parses a JSON object of profiles, whose settings may be
+// strings or numbers.
+func parseJSONProfiles(data []byte) (profiles, error) {
+	raw := map[string]map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	p := make(profiles, len(raw))
+	for name, settings := range raw {
+		p[name] = make(map[string]string, len(settings))
+		for key, value := range settings {
+			var str string
+			if err := json.Unmarshal(value, &str); err == nil {
+				p[name][key] = str
+				continue
+			}
+			var num json.Number
+			if err := json.Unmarshal(value, &num); err != nil {
+				return nil, fmt.Errorf("profile %q: %s must be a string or number", name, key)
+			}
+			p[name][key] = num.String()
+		}
+	}
+
+	return p, nil
+}
+
+// parseINI parses a minimal INI dialect: "[section]" headers, "key = value"
+// pairs, and full-line comments beginning with "#" or ";". Values may be
+// wrapped in double quotes. Keys outside any section belong to
+// DefaultProfile.
+func parseINI(data []byte) (profiles, error) {
+	p := profiles{}
+	section := DefaultProfile
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", lineNo)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			// AWS-style "[profile name]" headers are also accepted.
+			section = strings.TrimSpace(strings.TrimPrefix(section, "profile "))
+			if section == "" {
+				return nil, fmt.Errorf("line %d: empty section name", lineNo)
+			}
+			if p[section] == nil {
+				p[section] = map[string]string{}
+			}
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if key == "" {
+				return nil, fmt.Errorf("line %d: empty key", lineNo)
+			}
+			if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+				value = value[1 : len(value)-1]
+			}
+			if p[section] == nil {
+				p[section] = map[string]string{}
+			}
+			p[section][key] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading: %w", err)
+	}
+
+	return p, nil
+}
+
+// Environment variables read by NewClientFromProfile.
+const (
+	// EnvConfigFile overrides the path of the profiles config file.
+	EnvConfigFile = "BONSAI_CONFIG_FILE"
+	// EnvProfile selects the profile used when none is specified.
+	EnvProfile = "BONSAI_PROFILE"
+	// EnvEndpoint overrides the endpoint of the selected profile.
+	EnvEndpoint = "BONSAI_API_ENDPOINT"
+)
+
+// Profile config file settings.
+const (
+	profileKeyEndpoint                   = "endpoint"
+	profileKeyApplicationName            = "application_name"
+	profileKeyApplicationVersion         = "application_version"
+	profileKeyRateLimitBurst             = "rate_limit_burst"
+	profileKeyRateLimitInterval          = "rate_limit_interval"
+	profileKeyProvisionRateLimitBurst    = "provision_rate_limit_burst"
+	profileKeyProvisionRateLimitInterval = "provision_rate_limit_interval"
+)
+
+// ErrUnknownProfile is returned when a named profile isn't present in the
+// profiles config file.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// RateLimit configures a token bucket rate limiter, as used by the Client.
+type RateLimit struct {
+	// Burst is the size of the token bucket.
+	Burst int
+	// Interval is the interval at which the token bucket is refilled.
+	Interval time.Duration
+}
+
+// IsZero reports whether l is unset.
+func (l RateLimit) IsZero() bool {
+	return l == RateLimit{}
+}
+
+// Limiter returns a rate.Limiter applying l.
+func (l RateLimit) Limiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Every(l.Interval), l.Burst)
+}
+
+// Profile holds the Client settings of a named profile, as read from a
+// profiles config file with LoadProfile:
+//
+//	[production]
+//	endpoint = https://api.bonsai.io
+//	access_key = ...
+//	access_token = ...
+//	application_name = my-app
+//	application_version = 1.2.3
+//	rate_limit_burst = 60
+//	rate_limit_interval = 1m
+//	provision_rate_limit_burst = 5
+//	provision_rate_limit_interval = 1m
+//
+// Every setting is optional; the Client's defaults apply to those unset.
+// Rate limits must set both a burst and an interval, or neither.
+type Profile struct {
+	Name               string
+	Endpoint           string
+	Credentials        CredentialPair
+	Application        Application
+	DefaultRateLimit   RateLimit
+	ProvisionRateLimit RateLimit
+}
+
+// ClientOptions returns the ClientOptions applying p's settings.
+func (p Profile) ClientOptions() []ClientOption {
+	var opts []ClientOption
+
+	if p.Endpoint != "" {
+		opts = append(opts, WithEndpoint(p.Endpoint))
+	}
+	if p.Credentials.NotEmpty() {
+		opts = append(opts, WithCredentialPair(p.Credentials))
+	}
+	if p.Application != (Application{}) {
+		opts = append(opts, WithApplication(p.Application))
+	}
+	if !p.DefaultRateLimit.IsZero() {
+		opts = append(opts, WithDefaultRateLimit(p.DefaultRateLimit.Limiter()))
+	}
+	if !p.ProvisionRateLimit.IsZero() {
+		opts = append(opts, WithProvisionRateLimit(p.ProvisionRateLimit.Limiter()))
+	}
+
+	return opts
+}
+
+// DefaultConfigPath returns the default path of the profiles config file:
+// "bonsai/config" within $XDG_CONFIG_HOME, or ~/.config if unset.
+func DefaultConfigPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "bonsai", "config"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating config file: %w", err)
+	}
+	return filepath.Join(home, ".config", "bonsai", "config"), nil
+}
+
+// LoadProfile reads the named profile from the JSON or INI profiles config
+// file at path, validating its settings. An empty name selects
+// DefaultProfile.
+//
+// If the profile isn't present in the file, ErrUnknownProfile is returned.
+func LoadProfile(path, name string) (Profile, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("reading config file: %w", err)
+	}
+
+	all, err := parseProfiles(path, data)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	settings, ok := all[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w %q in %s", ErrUnknownProfile, name, path)
+	}
+
+	profile, err := newProfile(name, settings)
+	if err != nil {
+		return Profile{}, fmt.Errorf("profile %q in %s: %w", name, path, err)
+	}
+	return profile, nil
+}
+
+// NewClientFromProfile returns a Client configured by the named profile,
+// followed by opts. An empty name selects the profile named by
+// BONSAI_PROFILE, or DefaultProfile.
+//
+// The profile is read from the file named by BONSAI_CONFIG_FILE, or
+// DefaultConfigPath. The BONSAI_API_ENDPOINT, BONSAI_API_KEY and
+// BONSAI_API_TOKEN environment variables override the profile's settings;
+// if they supply credentials, the default profile, or the config file
+// itself, may be absent.
+//
+// Malformed settings, and profiles without credentials, are reported as an
+// error, rather than on the Client's first request.
+func NewClientFromProfile(name string, opts ...ClientOption) (*Client, error) {
+	profile, err := loadProfileFromEnv(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.Credentials.Empty() {
+		return nil, fmt.Errorf(
+			"%w: profile %q must set %s and %s, or %s and %s must be set",
+			ErrNoCredentials, profile.Name, profileKeyAccessKey, profileKeyAccessToken, EnvAccessKey, EnvAccessToken,
+		)
+	}
+
+	return NewClient(append(profile.ClientOptions(), opts...)...), nil
+}
+
+// loadProfileFromEnv loads the named profile, as located by the
+// environment, and applies overrides from the environment.
+func loadProfileFromEnv(name string) (Profile, error) {
+	explicit := name != ""
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+		explicit = name != ""
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	path := os.Getenv(EnvConfigFile)
+	if path == "" {
+		var err error
+		if path, err = DefaultConfigPath(); err != nil {
+			return Profile{}, err
+		}
+	}
+
+	profile, err := LoadProfile(path, name)
+	switch {
+	case err == nil:
+	case !explicit && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrUnknownProfile)):
+		// The default profile is optional, as the environment may supply
+		// all required settings.
+		profile = Profile{Name: name}
+	default:
+		return Profile{}, err
+	}
+
+	if endpoint := os.Getenv(EnvEndpoint); endpoint != "" {
+		profile.Endpoint = endpoint
+	}
+
+	if key, token := os.Getenv(EnvAccessKey), os.Getenv(EnvAccessToken); key != "" || token != "" {
+		if key == "" || token == "" {
+			return Profile{}, fmt.Errorf("%s and %s must be set together", EnvAccessKey, EnvAccessToken)
+		}
+		profile.Credentials, err = newCredentialPair(key, token)
+		if err != nil {
+			return Profile{}, fmt.Errorf("reading credentials from %s and %s: %w", EnvAccessKey, EnvAccessToken, err)
+		}
+	}
+
+	return profile, nil
+}
+
+// newProfile validates settings, returning the Profile they describe.
+func newProfile(name string, settings map[string]string) (Profile, error) {
+	profile := Profile{Name: name}
+
+	// Visit settings in a stable order, such that errors are too.
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := profile.set(key, settings[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if (profile.Credentials.AccessKey == "") != (profile.Credentials.AccessToken == "") {
+		errs = append(errs, fmt.Errorf("%s and %s must be set together", profileKeyAccessKey, profileKeyAccessToken))
+	}
+	if l := profile.DefaultRateLimit; (l.Burst == 0) != (l.Interval == 0) {
+		errs = append(errs, fmt.Errorf(
+			"%s and %s must be set together", profileKeyRateLimitBurst, profileKeyRateLimitInterval,
+		))
+	}
+	if l := profile.ProvisionRateLimit; (l.Burst == 0) != (l.Interval == 0) {
+		errs = append(errs, fmt.Errorf(
+			"%s and %s must be set together", profileKeyProvisionRateLimitBurst, profileKeyProvisionRateLimitInterval,
+		))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return Profile{}, err
+	}
+	return profile, nil
+}
+
+// set validates and applies a single profile setting.
+func (p *Profile) set(key, value string) error {
+	var err error
+
+	switch key {
+	case profileKeyEndpoint:
+		u, parseErr := url.Parse(value)
+		if parseErr != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid %s %q: must be an absolute URL", key, value)
+		}
+		p.Endpoint = value
+	case profileKeyAccessKey:
+		p.Credentials.AccessKey, err = NewAccessKey(value)
+	case profileKeyAccessToken:
+		p.Credentials.AccessToken, err = NewAccessToken(value)
+	case profileKeyApplicationName:
+		p.Application.Name = value
+	case profileKeyApplicationVersion:
+		p.Application.Version = value
+	case profileKeyRateLimitBurst:
+		p.DefaultRateLimit.Burst, err = parseBurst(value)
+	case profileKeyRateLimitInterval:
+		p.DefaultRateLimit.Interval, err = parseInterval(value)
+	case profileKeyProvisionRateLimitBurst:
+		p.ProvisionRateLimit.Burst, err = parseBurst(value)
+	case profileKeyProvisionRateLimitInterval:
+		p.ProvisionRateLimit.Interval, err = parseInterval(value)
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+func parseBurst(value string) (int, error) {
+	burst, err := strconv.Atoi(value)
+	if err != nil || burst <= 0 {
+		return 0, fmt.Errorf("%q is not a positive integer", value)
+	}
+	return burst, nil
+}
+
+func parseInterval(value string) (time.Duration, error) {
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("%q is not a positive duration", value)
+	}
+	return interval, nil
+}
@@ -0,0 +1,175 @@
+package bonsai_test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+const testProfilesConfig = `
+[default]
+access_key = default-key
+access_token = default-token
+
+[staging]
+endpoint = https://staging.example.com
+access_key = staging-key
+access_token = staging-token
+application_name = deployer
+application_version = 1.2.3
+rate_limit_burst = 30
+rate_limit_interval = 2s
+provision_rate_limit_burst = 2
+provision_rate_limit_interval = 1m
+
+[incomplete]
+access_key = incomplete-key
+rate_limit_burst = 10
+provision_rate_limit_burst = ten
+provision_rate_limit_interval = 1m
+colour = blue
+`
+
+func (s *ClientMockTestSuite) TestLoadProfile() {
+	dir := s.T().TempDir()
+	path := filepath.Join(dir, "config")
+	s.writeFile(path, testProfilesConfig)
+
+	s.Run("default", func() {
+		profile, err := bonsai.LoadProfile(path, "")
+		s.NoError(err)
+		s.Equal(bonsai.Profile{
+			Name:        bonsai.DefaultProfile,
+			Credentials: bonsai.CredentialPair{AccessKey: "default-key", AccessToken: "default-token"},
+		}, profile)
+	})
+
+	s.Run("all settings", func() {
+		profile, err := bonsai.LoadProfile(path, "staging")
+		s.NoError(err)
+		s.Equal(bonsai.Profile{
+			Name:               "staging",
+			Endpoint:           "https://staging.example.com",
+			Credentials:        bonsai.CredentialPair{AccessKey: "staging-key", AccessToken: "staging-token"},
+			Application:        bonsai.Application{Name: "deployer", Version: "1.2.3"},
+			DefaultRateLimit:   bonsai.RateLimit{Burst: 30, Interval: 2 * time.Second},
+			ProvisionRateLimit: bonsai.RateLimit{Burst: 2, Interval: time.Minute},
+		}, profile)
+	})
+
+	s.Run("json", func() {
+		jsonPath := filepath.Join(dir, "config.json")
+		s.writeFile(jsonPath, `{"prod": {"access_key": "k", "access_token": "t", "rate_limit_burst": 5, "rate_limit_interval": "1s"}}`)
+
+		profile, err := bonsai.LoadProfile(jsonPath, "prod")
+		s.NoError(err)
+		s.Equal(bonsai.RateLimit{Burst: 5, Interval: time.Second}, profile.DefaultRateLimit)
+	})
+
+	s.Run("unknown profile", func() {
+		_, err := bonsai.LoadProfile(path, "production")
+		s.ErrorIs(err, bonsai.ErrUnknownProfile)
+	})
+
+	s.Run("malformed settings", func() {
+		_, err := bonsai.LoadProfile(path, "incomplete")
+		s.ErrorContains(err, `unknown setting "colour"`)
+		s.ErrorContains(err, `invalid provision_rate_limit_burst: "ten" is not a positive integer`)
+		s.ErrorContains(err, "access_key and access_token must be set together")
+		s.ErrorContains(err, "rate_limit_burst and rate_limit_interval must be set together")
+	})
+
+	s.Run("invalid credentials", func() {
+		invalidPath := filepath.Join(dir, "invalid.json")
+		s.writeFile(invalidPath, `{"default": {"access_key": "bad\nkey", "access_token": "t"}}`)
+
+		_, err := bonsai.LoadProfile(invalidPath, "")
+		s.ErrorContains(err, "invalid access_key")
+	})
+}
+
+func (s *ClientMockTestSuite) TestNewClientFromProfile() {
+	ctx := context.Background()
+	path := filepath.Join(s.T().TempDir(), "config")
+	s.writeFile(path, testProfilesConfig)
+
+	s.T().Setenv(bonsai.EnvConfigFile, path)
+	s.T().Setenv(bonsai.EnvProfile, "")
+	s.T().Setenv(bonsai.EnvEndpoint, "")
+	s.T().Setenv(bonsai.EnvAccessKey, "")
+	s.T().Setenv(bonsai.EnvAccessToken, "")
+
+	s.Run("named profile", func() {
+		client, err := bonsai.NewClientFromProfile("staging")
+		s.NoError(err)
+
+		req, err := client.NewRequest(ctx, http.MethodGet, bonsai.PlanAPIBasePath, nil)
+		s.NoError(err)
+		s.Equal("https://staging.example.com"+bonsai.PlanAPIBasePath, req.URL.String())
+		s.Equal("deployer/1.2.3 "+bonsai.UserAgent, req.Header.Get("User-Agent"))
+		key, token, _ := req.BasicAuth()
+		s.Equal("staging-key", key)
+		s.Equal("staging-token", token)
+	})
+
+	s.Run("profile from environment", func() {
+		s.T().Setenv(bonsai.EnvProfile, "staging")
+
+		client, err := bonsai.NewClientFromProfile("")
+		s.NoError(err)
+
+		req, err := client.NewRequest(ctx, http.MethodGet, bonsai.PlanAPIBasePath, nil)
+		s.NoError(err)
+		s.Equal("staging.example.com", req.URL.Host)
+	})
+
+	s.Run("environment overrides", func() {
+		s.T().Setenv(bonsai.EnvEndpoint, s.server.URL+"/profiles")
+		s.T().Setenv(bonsai.EnvAccessKey, "env-key")
+		s.T().Setenv(bonsai.EnvAccessToken, "env-token")
+
+		client, err := bonsai.NewClientFromProfile("staging")
+		s.NoError(err)
+
+		req, err := client.NewRequest(ctx, http.MethodGet, bonsai.PlanAPIBasePath, nil)
+		s.NoError(err)
+		s.Equal(s.server.URL+"/profiles"+bonsai.PlanAPIBasePath, req.URL.String())
+		key, token, _ := req.BasicAuth()
+		s.Equal("env-key", key)
+		s.Equal("env-token", token)
+	})
+
+	s.Run("environment only", func() {
+		s.T().Setenv(bonsai.EnvConfigFile, filepath.Join(s.T().TempDir(), "missing"))
+		s.T().Setenv(bonsai.EnvAccessKey, "env-key")
+		s.T().Setenv(bonsai.EnvAccessToken, "env-token")
+
+		_, err := bonsai.NewClientFromProfile("")
+		s.NoError(err, "the default profile is optional")
+
+		_, err = bonsai.NewClientFromProfile("staging")
+		s.Error(err, "named profiles are required")
+	})
+
+	s.Run("invalid environment", func() {
+		s.T().Setenv(bonsai.EnvAccessKey, "env-key")
+
+		_, err := bonsai.NewClientFromProfile("")
+		s.ErrorContains(err, bonsai.EnvAccessKey+" and "+bonsai.EnvAccessToken+" must be set together")
+	})
+
+	s.Run("no credentials", func() {
+		s.T().Setenv(bonsai.EnvConfigFile, filepath.Join(s.T().TempDir(), "missing"))
+
+		_, err := bonsai.NewClientFromProfile("")
+		s.ErrorIs(err, bonsai.ErrNoCredentials)
+	})
+
+	s.Run("malformed profile", func() {
+		_, err := bonsai.NewClientFromProfile("incomplete")
+		s.ErrorContains(err, `unknown setting "colour"`)
+	})
+}
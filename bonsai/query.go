@@ -0,0 +1,202 @@
+package bonsai
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrNoMatchingPlans is returned by PlanQuery.First when no Plan matches.
+var ErrNoMatchingPlans = errors.New("no plans match query")
+
+// planFilter reports whether plan matches, returning it with its
+// AvailableReleases and AvailableSpaces narrowed to those which match.
+type planFilter func(plan Plan) (Plan, bool)
+
+// PlanQuery selects Plans from a Catalog. Queries are built by chaining
+// conditions, each of which must hold for a Plan to match:
+//
+//	plans, err := client.Catalog.Plans().
+//		WhereSingleTenant(false).
+//		InRegion("us-east-1").
+//		SupportsRelease("opensearch-2.6.0").
+//		MaxPriceCents(5000).
+//		All(ctx)
+//
+// Conditions on spaces and releases also narrow the AvailableSpaces and
+// AvailableReleases of the matching Plans to those satisfying them, such
+// that the results hold only valid choices. Plans with an unknown tenancy
+// or network never match conditions on them.
+//
+// A PlanQuery is immutable; each condition returns a new PlanQuery, so that
+// queries may be shared and extended safely.
+type PlanQuery struct {
+	catalog *Catalog
+	filters []planFilter
+}
+
+// Plans returns a PlanQuery selecting from all of the catalog's Plans.
+func (c *Catalog) Plans() PlanQuery {
+	return PlanQuery{catalog: c}
+}
+
+func (q PlanQuery) with(f planFilter) PlanQuery {
+	q.filters = append(slices.Clip(q.filters), f)
+	return q
+}
+
+// Where matches Plans for which match returns true.
+func (q PlanQuery) Where(match func(Plan) bool) PlanQuery {
+	return q.with(func(plan Plan) (Plan, bool) {
+		return plan, match(plan)
+	})
+}
+
+// WhereSingleTenant matches Plans whose tenancy is singleTenant.
+func (q PlanQuery) WhereSingleTenant(singleTenant bool) PlanQuery {
+	return q.Where(func(plan Plan) bool {
+		return plan.SingleTenant != nil && *plan.SingleTenant == singleTenant
+	})
+}
+
+// WherePrivateNetwork matches Plans whose network is private, or not.
+func (q PlanQuery) WherePrivateNetwork(private bool) PlanQuery {
+	return q.Where(func(plan Plan) bool {
+		return plan.PrivateNetwork != nil && *plan.PrivateNetwork == private
+	})
+}
+
+// MaxPriceCents matches Plans priced at most cents.
+func (q PlanQuery) MaxPriceCents(cents int64) PlanQuery {
+	return q.Where(func(plan Plan) bool {
+		return plan.PriceInCents <= cents
+	})
+}
+
+// InRegion matches Plans available in a Space in region. Regions may be
+// given with or without their cloud provider's prefix; "us-east-1" matches
+// the Spaces in "aws-us-east-1".
+func (q PlanQuery) InRegion(region string) PlanQuery {
+	return q.whereSpace(func(space Space) bool {
+		var provider string
+		if space.Cloud != nil {
+			provider = space.Cloud.Provider
+			if matchRegion(space.Cloud.Region, provider, region) {
+				return true
+			}
+		}
+		return matchRegion(space.Region, provider, region)
+	})
+}
+
+// OnProvider matches Plans available in a Space hosted by the cloud
+// provider, such as "aws" or "gcp".
+func (q PlanQuery) OnProvider(provider string) PlanQuery {
+	return q.whereSpace(func(space Space) bool {
+		return space.Cloud != nil && strings.EqualFold(space.Cloud.Provider, provider)
+	})
+}
+
+// InSpace matches Plans available in the Space with the given path.
+func (q PlanQuery) InSpace(spacePath string) PlanQuery {
+	return q.whereSpace(func(space Space) bool {
+		return space.Path == spacePath
+	})
+}
+
+// SupportsRelease matches Plans offering the Release with the given slug.
+func (q PlanQuery) SupportsRelease(slug string) PlanQuery {
+	return q.whereRelease(func(release Release) bool {
+		return release.Slug == slug
+	})
+}
+
+// SupportsServiceType matches Plans offering a Release of the service
+// type, such as "elasticsearch" or "opensearch".
+func (q PlanQuery) SupportsServiceType(serviceType string) PlanQuery {
+	return q.whereRelease(func(release Release) bool {
+		return strings.EqualFold(release.ServiceType, serviceType)
+	})
+}
+
+func (q PlanQuery) whereSpace(match func(Space) bool) PlanQuery {
+	return q.with(func(plan Plan) (Plan, bool) {
+		plan.AvailableSpaces = slices.DeleteFunc(slices.Clone(plan.AvailableSpaces), func(s Space) bool {
+			return !match(s)
+		})
+		return plan, len(plan.AvailableSpaces) > 0
+	})
+}
+
+func (q PlanQuery) whereRelease(match func(Release) bool) PlanQuery {
+	return q.with(func(plan Plan) (Plan, bool) {
+		plan.AvailableReleases = slices.DeleteFunc(slices.Clone(plan.AvailableReleases), func(r Release) bool {
+			return !match(r)
+		})
+		return plan, len(plan.AvailableReleases) > 0
+	})
+}
+
+// All returns the matching Plans, cheapest first, and then by slug. Their
+// AvailableReleases and AvailableSpaces are resolved per
+// PlanClient.AllExpanded.
+func (q PlanQuery) All(ctx context.Context) ([]Plan, error) {
+	plans, err := q.catalog.AllPlansExpanded(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Plan
+	for _, plan := range plans {
+		if plan, ok := q.match(plan); ok {
+			matches = append(matches, plan)
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b Plan) int {
+		return cmp.Or(cmp.Compare(a.PriceInCents, b.PriceInCents), strings.Compare(a.Slug, b.Slug))
+	})
+
+	return matches, nil
+}
+
+// First returns the cheapest matching Plan. If none match,
+// ErrNoMatchingPlans is returned.
+func (q PlanQuery) First(ctx context.Context) (Plan, error) {
+	plans, err := q.All(ctx)
+	if err != nil {
+		return Plan{}, err
+	}
+	if len(plans) == 0 {
+		return Plan{}, ErrNoMatchingPlans
+	}
+	return plans[0], nil
+}
+
+func (q PlanQuery) match(plan Plan) (Plan, bool) {
+	for _, f := range q.filters {
+		var ok bool
+		if plan, ok = f(plan); !ok {
+			return plan, false
+		}
+	}
+	return plan, true
+}
+
+// matchRegion reports whether region names want, ignoring case, with or
+// without the prefix of its cloud provider, if known.
+func matchRegion(region, provider, want string) bool {
+	if region == "" || want == "" {
+		return false
+	}
+	if strings.EqualFold(region, want) {
+		return true
+	}
+	if provider == "" || len(region) <= len(provider)+1 {
+		return false
+	}
+	prefix, unprefixed := region[:len(provider)+1], region[len(provider)+1:]
+	return strings.EqualFold(prefix, provider+"-") && strings.EqualFold(unprefixed, want)
+}
@@ -0,0 +1,156 @@
+package bonsai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/bonsaitest"
+)
+
+type PlanQueryTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all HTTP request tests
+	suite.Suite
+
+	// server is the fake API server, seeded with the default catalog
+	server *bonsaitest.Server
+	// client is wired to make requests against server
+	client *bonsai.Client
+}
+
+func (s *PlanQueryTestSuite) SetupTest() {
+	s.server = bonsaitest.NewServer()
+	s.client = s.server.Client()
+
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func (s *PlanQueryTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestPlanQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(PlanQueryTestSuite))
+}
+
+func slugs(plans []bonsai.Plan) []string {
+	result := make([]string, len(plans))
+	for i, p := range plans {
+		result[i] = p.Slug
+	}
+	return result
+}
+
+func (s *PlanQueryTestSuite) TestAll() {
+	ctx := context.Background()
+	catalog := s.client.Catalog
+
+	testCases := []struct {
+		name  string
+		query bonsai.PlanQuery
+		want  []string
+	}{
+		{
+			name:  "all plans, cheapest first",
+			query: catalog.Plans(),
+			want:  []string{"sandbox-aws-us-east-1", "standard-sm", "business-sm"},
+		},
+		{
+			name:  "tenancy",
+			query: catalog.Plans().WhereSingleTenant(true),
+			want:  []string{"business-sm"},
+		},
+		{
+			name:  "network",
+			query: catalog.Plans().WherePrivateNetwork(true),
+			want:  []string{},
+		},
+		{
+			name:  "price",
+			query: catalog.Plans().MaxPriceCents(5000),
+			want:  []string{"sandbox-aws-us-east-1", "standard-sm"},
+		},
+		{
+			name:  "region without provider prefix",
+			query: catalog.Plans().InRegion("eu-west-1"),
+			want:  []string{"standard-sm", "business-sm"},
+		},
+		{
+			name:  "region with provider prefix",
+			query: catalog.Plans().InRegion("AWS-EU-WEST-1"),
+			want:  []string{"standard-sm", "business-sm"},
+		},
+		{
+			name:  "partial region",
+			query: catalog.Plans().InRegion("east-1"),
+			want:  []string{},
+		},
+		{
+			name:  "provider",
+			query: catalog.Plans().OnProvider("gcp"),
+			want:  []string{"standard-sm"},
+		},
+		{
+			name:  "release",
+			query: catalog.Plans().SupportsRelease("opensearch-2.6.0"),
+			want:  []string{"business-sm"},
+		},
+		{
+			name:  "service type",
+			query: catalog.Plans().SupportsServiceType("OpenSearch"),
+			want:  []string{"sandbox-aws-us-east-1", "standard-sm", "business-sm"},
+		},
+		{
+			name: "combined",
+			query: catalog.Plans().
+				WhereSingleTenant(false).
+				InRegion("us-east-1").
+				SupportsRelease("opensearch-2.6.0-mt").
+				MaxPriceCents(5000),
+			want: []string{"sandbox-aws-us-east-1", "standard-sm"},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			plans, err := tc.query.All(ctx)
+			s.NoError(err)
+			s.Equal(tc.want, slugs(plans), "matching plans")
+		})
+	}
+}
+
+func (s *PlanQueryTestSuite) TestNarrowing() {
+	ctx := context.Background()
+	base := s.client.Catalog.Plans().WhereSingleTenant(false)
+
+	plan, err := base.OnProvider("gcp").First(ctx)
+	s.NoError(err)
+	s.Equal("standard-sm", plan.Slug)
+	s.Len(plan.AvailableSpaces, 1, "spaces are narrowed to those matching")
+	s.Equal("gcp-us-east4", plan.AvailableSpaces[0].Cloud.Region, "spaces are expanded")
+
+	plan, err = base.SupportsServiceType("elasticsearch").First(ctx)
+	s.NoError(err)
+	s.Equal("sandbox-aws-us-east-1", plan.Slug)
+	s.Len(plan.AvailableReleases, 1, "releases are narrowed to those matching")
+	s.Equal("7.10.2", plan.AvailableReleases[0].Version, "releases are expanded")
+
+	plans, err := base.All(ctx)
+	s.NoError(err)
+	s.Len(plans[1].AvailableSpaces, 3, "narrowing doesn't affect other queries")
+
+	_, err = base.InRegion("ap-southeast-2").First(ctx)
+	s.ErrorIs(err, bonsai.ErrNoMatchingPlans)
+
+	requests := len(s.server.Requests())
+	_, err = s.client.Catalog.Plans().All(ctx)
+	s.NoError(err)
+	s.Len(s.server.Requests(), requests, "the expanded catalog is cached")
+}
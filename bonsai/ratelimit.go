@@ -0,0 +1,172 @@
+package bonsai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Rate limit response headers, as sent by servers implementing either the
+// IETF RateLimit header fields draft, or the widespread X-RateLimit
+// convention.
+//
+// ref: https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+const (
+	HeaderRateLimitLimit      = "RateLimit-Limit"
+	HeaderRateLimitRemaining  = "RateLimit-Remaining"
+	HeaderRateLimitReset      = "RateLimit-Reset"
+	HeaderXRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderXRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderXRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimitState is the server's view of the client's rate limit, as
+// reported in response headers.
+type RateLimitState struct {
+	// Limit is the number of requests allowed in the current window.
+	Limit int
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+	// Reset is the time remaining until the current window resets.
+	Reset time.Duration
+}
+
+// RateLimit returns the rate limit state reported by the server in the
+// response headers, if any.
+func (r *Response) RateLimit() (RateLimitState, bool) {
+	if r == nil || r.httpResponse == nil {
+		return RateLimitState{}, false
+	}
+	return parseRateLimitHeaders(r.Header)
+}
+
+func parseRateLimitHeaders(h http.Header) (RateLimitState, bool) {
+	var state RateLimitState
+
+	header := func(names ...string) (int, bool) {
+		for _, name := range names {
+			if v := h.Get(name); v != "" {
+				i, err := strconv.Atoi(v)
+				return i, err == nil
+			}
+		}
+		return 0, false
+	}
+
+	remaining, ok := header(HeaderRateLimitRemaining, HeaderXRateLimitRemaining)
+	if !ok {
+		return state, false
+	}
+	state.Remaining = max(remaining, 0)
+
+	if limit, ok := header(HeaderRateLimitLimit, HeaderXRateLimitLimit); ok {
+		state.Limit = limit
+	}
+	if reset, ok := header(HeaderRateLimitReset, HeaderXRateLimitReset); ok {
+		state.Reset = time.Duration(max(reset, 0)) * time.Second
+	}
+
+	return state, true
+}
+
+// RouteRateLimit applies a dedicated rate limiter to requests matching its
+// Method and Pattern. Route rate limits are applied in addition to the
+// Client's default rate limit.
+type RouteRateLimit struct {
+	// Method is the HTTP method of matching requests; empty matches any method.
+	Method string
+	// Pattern is matched against the request path, relative to the Client's
+	// endpoint, with [path.Match] - for example, "/clusters/*".
+	//
+	// Note that path.Match wildcards don't match the path separator.
+	Pattern string
+	// Limiter is the rate limiter applied to matching requests.
+	Limiter *rate.Limiter
+}
+
+func (r RouteRateLimit) matches(method, reqPath string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	ok, err := path.Match(r.Pattern, reqPath)
+	return err == nil && ok
+}
+
+// WithRouteRateLimit configures a dedicated rate limit for requests matching
+// the method and path pattern; see [RouteRateLimit].
+//
+// Route rate limits are consulted in the order they're configured, and only
+// the first match applies. They take precedence over the rate limit
+// configured with WithProvisionRateLimit.
+func WithRouteRateLimit(method, pattern string, l *rate.Limiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter.routes = append(c.rateLimiter.routes, RouteRateLimit{
+			Method:  method,
+			Pattern: pattern,
+			Limiter: l,
+		})
+	}
+}
+
+// WithRateLimitHeaderSync configures whether the Client's default rate
+// limiter is drained to match the remaining request allowance reported by
+// the server in response headers. Enabled by default.
+func WithRateLimitHeaderSync(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter.headerSync = enabled
+	}
+}
+
+// routeLimiter returns the rate limiter dedicated to the route, if any.
+func (l *ClientLimiter) routeLimiter(method, reqPath string) *rate.Limiter {
+	for _, r := range l.routes {
+		if r.matches(method, reqPath) {
+			return r.Limiter
+		}
+	}
+
+	if method == http.MethodPost && reqPath == ClusterAPIBasePath {
+		return l.provisionLimiter
+	}
+
+	return nil
+}
+
+// wait blocks until both the limiter dedicated to the route, if any, and the
+// default limiter allow a request to proceed.
+func (l *ClientLimiter) wait(ctx context.Context, method, reqPath string) error {
+	if rl := l.routeLimiter(method, reqPath); rl != nil {
+		if err := rl.Wait(ctx); err != nil {
+			return fmt.Errorf("awaiting rate limit for %s %s: %w", method, reqPath, err)
+		}
+	}
+
+	if err := l.Wait(ctx); err != nil {
+		return fmt.Errorf("awaiting default rate limit: %w", err)
+	}
+
+	return nil
+}
+
+// observe drains the default limiter, such that it holds no more tokens
+// than the server reports as remaining.
+func (l *ClientLimiter) observe(h http.Header) {
+	if !l.headerSync {
+		return
+	}
+
+	state, ok := parseRateLimitHeaders(h)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if excess := int(l.TokensAt(now)) - state.Remaining; excess > 0 {
+		l.AllowN(now, excess)
+	}
+}
@@ -0,0 +1,99 @@
+package bonsai
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func (s *ClientImplTestSuite) TestClientLimiterRouteLimiter() {
+	var (
+		updates = rate.NewLimiter(rate.Every(time.Minute), 1)
+		reads   = rate.NewLimiter(rate.Every(time.Minute), 1)
+		anyPost = rate.NewLimiter(rate.Every(time.Minute), 1)
+	)
+
+	c := NewClient(
+		WithRouteRateLimit(http.MethodPut, "/clusters/*", updates),
+		WithRouteRateLimit(http.MethodGet, "", reads),
+		WithRouteRateLimit("", "/plans", reads),
+		WithRouteRateLimit(http.MethodPost, "/spaces", anyPost),
+	)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		expect *rate.Limiter
+	}{
+		{name: "wildcard pattern", method: http.MethodPut, path: "/clusters/a-1234", expect: updates},
+		{name: "wildcard doesn't span segments", method: http.MethodPut, path: "/clusters/a/b", expect: nil},
+		{name: "empty method matches any", method: http.MethodDelete, path: "/plans", expect: reads},
+		{name: "first match wins", method: http.MethodPost, path: "/spaces", expect: anyPost},
+		{name: "built-in provision route", method: http.MethodPost, path: ClusterAPIBasePath, expect: c.rateLimiter.provisionLimiter},
+		{name: "no match", method: http.MethodGet, path: "/releases", expect: nil},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.Same(tc.expect, c.rateLimiter.routeLimiter(tc.method, tc.path))
+		})
+	}
+}
+
+func (s *ClientImplTestSuite) TestParseRateLimitHeaders() {
+	testCases := []struct {
+		name     string
+		received http.Header
+		expect   RateLimitState
+		ok       bool
+	}{
+		{
+			name: "ietf draft headers",
+			received: http.Header{
+				HeaderRateLimitLimit:     []string{"60"},
+				HeaderRateLimitRemaining: []string{"12"},
+				HeaderRateLimitReset:     []string{"30"},
+			},
+			expect: RateLimitState{Limit: 60, Remaining: 12, Reset: 30 * time.Second},
+			ok:     true,
+		},
+		{
+			name: "x-ratelimit headers without reset",
+			received: http.Header{
+				HeaderXRateLimitLimit:     []string{"5"},
+				HeaderXRateLimitRemaining: []string{"1"},
+			},
+			expect: RateLimitState{Limit: 5, Remaining: 1},
+			ok:     true,
+		},
+		{
+			name:     "absent",
+			received: http.Header{},
+			ok:       false,
+		},
+		{
+			name: "malformed",
+			received: http.Header{
+				HeaderRateLimitRemaining: []string{"lots"},
+			},
+			ok: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			h := http.Header{}
+			for k, v := range tc.received {
+				h.Set(k, v[0])
+			}
+
+			state, ok := parseRateLimitHeaders(h)
+			s.Equal(tc.ok, ok)
+			if tc.ok {
+				s.Equal(tc.expect, state)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package bonsai_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+func (s *ClientMockTestSuite) TestClient_RouteRateLimit() {
+	s.serveMux.Post("/ratelimit/provision", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	s.serveMux.Get("/ratelimit/reads", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// newSingleUseLimiter returns a limiter which allows a single request per hour.
+	newSingleUseLimiter := func() *rate.Limiter {
+		return rate.NewLimiter(rate.Every(time.Hour), 1)
+	}
+
+	do := func(client *bonsai.Client, method, p string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		req, err := client.NewRequest(ctx, method, p, nil)
+		s.NoError(err, "request creation returns no error")
+
+		_, err = client.Do(ctx, req)
+		return err
+	}
+
+	s.Run("provision limit applies to Client.Do", func() {
+		client := bonsai.NewClient(
+			bonsai.WithEndpoint(s.server.URL),
+			bonsai.WithProvisionRateLimit(newSingleUseLimiter()),
+			bonsai.WithRouteRateLimit(http.MethodPost, "/ratelimit/provision", newSingleUseLimiter()),
+		)
+
+		s.Require().NoError(do(client, http.MethodPost, "/ratelimit/provision"), "first request is allowed")
+		s.Error(do(client, http.MethodPost, "/ratelimit/provision"), "second request exceeds route limit")
+		s.NoError(do(client, http.MethodGet, "/ratelimit/reads"), "other routes are unaffected")
+	})
+
+	s.Run("route limits are matched by method", func() {
+		client := bonsai.NewClient(
+			bonsai.WithEndpoint(s.server.URL),
+			bonsai.WithRouteRateLimit(http.MethodGet, "/ratelimit/*", newSingleUseLimiter()),
+		)
+
+		s.Require().NoError(do(client, http.MethodGet, "/ratelimit/reads"), "first request is allowed")
+		s.Error(do(client, http.MethodGet, "/ratelimit/reads"), "second request exceeds route limit")
+		s.NoError(do(client, http.MethodPost, "/ratelimit/provision"), "other methods are unaffected")
+	})
+}
+
+func (s *ClientMockTestSuite) TestClient_RateLimitHeaderSync() {
+	const p = "/ratelimit/headers"
+
+	s.serveMux.Get(p, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(bonsai.HeaderXRateLimitLimit, "60")
+		w.Header().Set(bonsai.HeaderXRateLimitRemaining, "0")
+		w.Header().Set(bonsai.HeaderXRateLimitReset, "42")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name      string
+		sync      bool
+		expectErr bool
+	}{
+		{name: "enabled", sync: true, expectErr: true},
+		{name: "disabled", sync: false, expectErr: false},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			client := bonsai.NewClient(
+				bonsai.WithEndpoint(s.server.URL),
+				bonsai.WithDefaultRateLimit(rate.NewLimiter(rate.Every(time.Hour), 10)),
+				bonsai.WithRateLimitHeaderSync(tc.sync),
+			)
+
+			req, err := client.NewRequest(context.Background(), http.MethodGet, p, nil)
+			s.NoError(err, "request creation returns no error")
+
+			resp, err := client.Do(context.Background(), req)
+			s.NoError(err, "first request is allowed")
+
+			state, ok := resp.RateLimit()
+			s.True(ok, "rate limit state is reported")
+			s.Equal(bonsai.RateLimitState{Limit: 60, Remaining: 0, Reset: 42 * time.Second}, state)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			req, err = client.NewRequest(ctx, http.MethodGet, p, nil)
+			s.NoError(err, "request creation returns no error")
+
+			_, err = client.Do(ctx, req)
+			if tc.expectErr {
+				s.Error(err, "limiter was drained per server headers")
+			} else {
+				s.NoError(err, "limiter ignores server headers")
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package reconcile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// ActionType identifies the change an Action makes to a cluster.
+type ActionType string
+
+const (
+	ActionCreate  ActionType = "create"
+	ActionUpdate  ActionType = "update"
+	ActionDestroy ActionType = "destroy"
+)
+
+// symbol returns the diff prefix for the action type.
+func (t ActionType) symbol() string {
+	switch t {
+	case ActionCreate:
+		return "+"
+	case ActionUpdate:
+		return "~"
+	case ActionDestroy:
+		return "-"
+	default:
+		return "?"
+	}
+}
+
+// Change describes a single field of a cluster changing value.
+type Change struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Action is a single change to be made to a cluster.
+type Action struct {
+	Type ActionType
+	// Name of the cluster the action applies to.
+	Name string
+	// Current holds the existing cluster, for update and destroy actions.
+	Current *bonsai.Cluster
+	// Desired holds the desired cluster, for create and update actions.
+	Desired *Cluster
+	// Changes holds the fields changed by an update action.
+	Changes []Change
+}
+
+// Destructive reports whether applying the action destroys a cluster.
+func (a Action) Destructive() bool {
+	return a.Type == ActionDestroy
+}
+
+// String returns a single-line summary of the action.
+func (a Action) String() string {
+	if a.Current != nil {
+		return fmt.Sprintf("%s %s cluster %q (%s)", a.Type.symbol(), a.Type, a.Name, a.Current.Slug)
+	}
+	return fmt.Sprintf("%s %s cluster %q", a.Type.symbol(), a.Type, a.Name)
+}
+
+// Plan holds the actions needed to bring the clusters on an account to
+// their desired state, in the order they'll be applied.
+type Plan struct {
+	Actions []Action
+}
+
+// Empty reports whether the plan holds no actions; that is, the clusters
+// are already in their desired state.
+func (p Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// Destructive reports whether applying the plan destroys any clusters.
+func (p Plan) Destructive() bool {
+	for _, a := range p.Actions {
+		if a.Destructive() {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteDiff writes a human-readable diff of the plan to w, suitable for
+// review before the plan is applied.
+func (p Plan) WriteDiff(w io.Writer) error {
+	var b strings.Builder
+
+	if p.Empty() {
+		b.WriteString("No changes. Clusters are up-to-date.\n")
+	}
+
+	var creates, updates, destroys int
+	for _, a := range p.Actions {
+		fmt.Fprintln(&b, a)
+
+		switch a.Type {
+		case ActionCreate:
+			creates++
+			for _, field := range [][2]string{
+				{"plan", a.Desired.Plan},
+				{"space", a.Desired.Space},
+				{"release", a.Desired.Release},
+			} {
+				if field[1] != "" {
+					fmt.Fprintf(&b, "    %s: %q\n", field[0], field[1])
+				}
+			}
+		case ActionUpdate:
+			updates++
+			for _, c := range a.Changes {
+				fmt.Fprintf(&b, "    %s: %q -> %q\n", c.Field, c.From, c.To)
+			}
+		case ActionDestroy:
+			destroys++
+		}
+	}
+
+	if !p.Empty() {
+		fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to destroy.\n", creates, updates, destroys)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// String returns the diff of the plan; see [Plan.WriteDiff].
+func (p Plan) String() string {
+	var b strings.Builder
+	_ = p.WriteDiff(&b)
+	return b.String()
+}
@@ -0,0 +1,357 @@
+// Package reconcile brings the clusters on a Bonsai account to a desired
+// state, declared as a set of clusters.
+//
+// A Reconciler compares the desired clusters against those listed by
+// [bonsai.ClusterClient.All], matching them by name, and computes a Plan of
+// the actions needed to create, update and destroy clusters. The plan may
+// be reviewed, with [Plan.WriteDiff], before it's applied.
+//
+//	r := reconcile.New(client)
+//	plan, err := r.Plan(ctx, desired)
+//	if err != nil {
+//		...
+//	}
+//	plan.WriteDiff(os.Stdout)
+//	results, err := r.Apply(ctx, plan)
+//
+// Changes that the API can't make to an existing cluster, such as moving it
+// to another space, are refused when planning. Destroying clusters is
+// refused when applying, unless allowed with [WithAllowDestroy].
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+var (
+	// ErrInvalidCluster is returned when a desired cluster is invalid.
+	ErrInvalidCluster = errors.New("invalid desired cluster")
+	// ErrAmbiguousCluster is returned when multiple clusters share a name, and
+	// so can't be matched to a desired cluster.
+	ErrAmbiguousCluster = errors.New("multiple clusters share a name")
+	// ErrImpossibleChange is returned when a desired cluster differs from the
+	// existing cluster in a way that can't be changed with an update.
+	ErrImpossibleChange = errors.New("change can't be applied to an existing cluster")
+	// ErrDestructiveChange is returned when applying a plan would destroy a
+	// cluster, and destroying clusters hasn't been allowed.
+	ErrDestructiveChange = errors.New("plan destroys clusters, which hasn't been allowed")
+)
+
+// ImpossibleChangeError describes a change to an existing cluster that
+// can't be expressed with [bonsai.ClusterUpdateOpts].
+type ImpossibleChangeError struct {
+	Name   string
+	Change Change
+}
+
+func (e ImpossibleChangeError) Error() string {
+	return fmt.Sprintf(
+		"cluster %q: %s can't change from %q to %q; destroy and re-create the cluster instead",
+		e.Name, e.Change.Field, e.Change.From, e.Change.To,
+	)
+}
+
+func (e ImpossibleChangeError) Is(target error) bool {
+	return target == ErrImpossibleChange
+}
+
+// Cluster is the desired state of a single cluster. Clusters are identified
+// by their Name.
+//
+// Empty fields aren't compared against existing clusters, and are left to
+// the API's defaults when creating clusters.
+type Cluster struct {
+	// Required. The name of the cluster.
+	Name string `json:"name"`
+	// The slug of the cluster's Plan.
+	Plan string `json:"plan,omitempty"`
+	// The path of the Space the cluster is deployed to.
+	Space string `json:"space,omitempty"`
+	// The slug of the search Release the cluster runs.
+	Release string `json:"release,omitempty"`
+}
+
+// Option configures a Reconciler.
+type Option func(*Reconciler)
+
+// WithAllowDestroy configures whether Apply may destroy clusters. Disabled
+// by default.
+func WithAllowDestroy(allow bool) Option {
+	return func(r *Reconciler) {
+		r.allowDestroy = allow
+	}
+}
+
+// WithManaged restricts the existing clusters considered by the Reconciler
+// to those for which managed returns true. Unmanaged clusters are never
+// updated or destroyed, and their names can't be used by desired clusters.
+//
+// By default, all clusters on the account are managed.
+func WithManaged(managed func(bonsai.Cluster) bool) Option {
+	return func(r *Reconciler) {
+		r.managed = managed
+	}
+}
+
+// WithWait configures whether Apply waits for each cluster to settle into
+// its new state before applying the next action. Enabled by default.
+func WithWait(enabled bool) Option {
+	return func(r *Reconciler) {
+		r.wait = enabled
+	}
+}
+
+// WithWaitOpts configures how Apply waits for clusters to settle. The
+// Targets are determined by each action, and so are ignored.
+func WithWaitOpts(opt bonsai.ClusterWaitOpts) Option {
+	return func(r *Reconciler) {
+		r.waitOpts = opt
+	}
+}
+
+// Reconciler plans and applies the changes needed to bring the clusters on
+// an account to their desired state.
+type Reconciler struct {
+	client *bonsai.Client
+
+	allowDestroy bool
+	managed      func(bonsai.Cluster) bool
+	wait         bool
+	waitOpts     bonsai.ClusterWaitOpts
+}
+
+// New returns a Reconciler which makes requests with client.
+//
+// Requests are subject to the client's rate limits and retry policy.
+func New(client *bonsai.Client, opts ...Option) *Reconciler {
+	r := &Reconciler{
+		client: client,
+		wait:   true,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Result is the outcome of applying a single Action.
+type Result struct {
+	Action Action
+	// Slug of the cluster the action was applied to.
+	Slug string
+	// Message returned by the API.
+	Message string
+	// Access holds the details for connecting to a created cluster,
+	// including its credentials, which are only available at creation.
+	Access bonsai.ClusterAccess
+	// Cluster holds the cluster once it settled, if waiting is enabled.
+	Cluster bonsai.Cluster
+}
+
+// Plan computes the actions needed to bring the clusters on the account to
+// the desired state.
+//
+// Existing clusters which aren't desired are destroyed. Changes which can't
+// be applied to an existing cluster are returned as ImpossibleChangeErrors.
+func (r *Reconciler) Plan(ctx context.Context, desired []Cluster) (Plan, error) {
+	if err := validate(desired); err != nil {
+		return Plan{}, err
+	}
+
+	clusters, err := r.client.Cluster.All(ctx)
+	if err != nil {
+		return Plan{}, fmt.Errorf("listing clusters: %w", err)
+	}
+
+	current, err := r.current(clusters)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return diff(desired, current)
+}
+
+// Apply applies the plan's actions in order, stopping at the first failure.
+//
+// The results of the actions applied, including any that failed, are
+// returned.
+func (r *Reconciler) Apply(ctx context.Context, plan Plan) ([]Result, error) {
+	if plan.Destructive() && !r.allowDestroy {
+		return nil, ErrDestructiveChange
+	}
+
+	results := make([]Result, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		result, err := r.apply(ctx, action)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", action, err)
+		}
+	}
+
+	return results, nil
+}
+
+// Reconcile plans and applies the changes needed to bring the clusters on
+// the account to the desired state.
+func (r *Reconciler) Reconcile(ctx context.Context, desired []Cluster) (Plan, []Result, error) {
+	plan, err := r.Plan(ctx, desired)
+	if err != nil {
+		return plan, nil, err
+	}
+
+	results, err := r.Apply(ctx, plan)
+	return plan, results, err
+}
+
+// current returns the managed clusters which haven't been, or aren't being,
+// deprovisioned, by name.
+func (r *Reconciler) current(clusters []bonsai.Cluster) (map[string]bonsai.Cluster, error) {
+	current := make(map[string]bonsai.Cluster, len(clusters))
+	for _, c := range clusters {
+		if c.State == bonsai.ClusterStateDeprovisioning || c.State == bonsai.ClusterStateDeprovisioned {
+			continue
+		}
+		if r.managed != nil && !r.managed(c) {
+			continue
+		}
+		if other, ok := current[c.Name]; ok {
+			return nil, fmt.Errorf("%w: %q (%s, %s)", ErrAmbiguousCluster, c.Name, other.Slug, c.Slug)
+		}
+		current[c.Name] = c
+	}
+	return current, nil
+}
+
+func (r *Reconciler) apply(ctx context.Context, action Action) (Result, error) {
+	result := Result{Action: action}
+
+	var target bonsai.ClusterState
+	switch action.Type {
+	case ActionCreate:
+		resp, err := r.client.Cluster.Create(ctx, bonsai.ClusterCreateOpts{
+			Name:    action.Desired.Name,
+			Plan:    action.Desired.Plan,
+			Space:   action.Desired.Space,
+			Release: action.Desired.Release,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Slug, result.Message, result.Access = bonsai.ClusterSlugFromURI(resp.Monitor), resp.Message, resp.Access
+		target = bonsai.ClusterStateProvisioned
+	case ActionUpdate:
+		resp, err := r.client.Cluster.Update(ctx, action.Current.Slug, bonsai.ClusterUpdateOpts{
+			Name: action.Current.Name,
+			Plan: action.Desired.Plan,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Slug, result.Message = action.Current.Slug, resp.Message
+		target = bonsai.ClusterStateProvisioned
+	case ActionDestroy:
+		resp, err := r.client.Cluster.Destroy(ctx, action.Current.Slug)
+		if err != nil {
+			return result, err
+		}
+		result.Slug, result.Message = action.Current.Slug, resp.Message
+		target = bonsai.ClusterStateDeprovisioned
+	default:
+		return result, fmt.Errorf("unknown action type %q", action.Type)
+	}
+
+	if !r.wait {
+		return result, nil
+	}
+	if result.Slug == "" {
+		return result, errors.New("unable to determine the slug of the cluster to wait for")
+	}
+
+	opt := r.waitOpts
+	opt.Targets = []bonsai.ClusterState{target}
+
+	cluster, err := r.client.Cluster.WaitForState(ctx, result.Slug, opt)
+	result.Cluster = cluster
+	return result, err
+}
+
+// validate checks that each desired cluster is named, and that no two share
+// a name.
+func validate(desired []Cluster) error {
+	seen := make(map[string]bool, len(desired))
+	for i, c := range desired {
+		if c.Name == "" {
+			return fmt.Errorf("%w: cluster %d: name can't be empty", ErrInvalidCluster, i)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("%w: name %q is used by multiple clusters", ErrInvalidCluster, c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return nil
+}
+
+// diff computes the plan which changes current, keyed by name, into desired.
+// Creates and updates are ordered as desired, followed by destroys.
+func diff(desired []Cluster, current map[string]bonsai.Cluster) (Plan, error) {
+	var (
+		plan Plan
+		errs []error
+	)
+
+	for i := range desired {
+		want := &desired[i]
+
+		have, ok := current[want.Name]
+		if !ok {
+			plan.Actions = append(plan.Actions, Action{Type: ActionCreate, Name: want.Name, Desired: want})
+			continue
+		}
+		delete(current, want.Name)
+
+		for _, c := range []Change{
+			{Field: "space", From: have.Space.Path, To: want.Space},
+			{Field: "release", From: have.Release.Slug, To: want.Release},
+		} {
+			if c.To != "" && c.From != c.To {
+				errs = append(errs, ImpossibleChangeError{Name: want.Name, Change: c})
+			}
+		}
+
+		if want.Plan != "" && want.Plan != have.Plan.Slug {
+			plan.Actions = append(plan.Actions, Action{
+				Type:    ActionUpdate,
+				Name:    want.Name,
+				Current: &have,
+				Desired: want,
+				Changes: []Change{{Field: "plan", From: have.Plan.Slug, To: want.Plan}},
+			})
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return Plan{}, err
+	}
+
+	destroys := make([]bonsai.Cluster, 0, len(current))
+	for _, c := range current {
+		destroys = append(destroys, c)
+	}
+	slices.SortFunc(destroys, func(a, b bonsai.Cluster) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for i := range destroys {
+		plan.Actions = append(plan.Actions, Action{Type: ActionDestroy, Name: destroys[i].Name, Current: &destroys[i]})
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,216 @@
+package reconcile_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/bonsaitest"
+	"github.com/omc/bonsai-api-go/v2/bonsai/reconcile"
+)
+
+type ReconcileTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all HTTP request tests
+	suite.Suite
+
+	// server is the fake API server, recreated for each test
+	server *bonsaitest.Server
+	// client is wired to make requests against server
+	client *bonsai.Client
+}
+
+func (s *ReconcileTestSuite) SetupTest() {
+	fixtures := bonsaitest.DefaultFixtures()
+	fixtures.Clusters = []bonsai.Cluster{
+		{
+			Slug:    "search-1234567890",
+			Name:    "search",
+			Plan:    bonsai.Plan{Slug: "sandbox-aws-us-east-1"},
+			Space:   bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+			Release: bonsai.Release{Slug: "opensearch-2.6.0-mt"},
+			State:   bonsai.ClusterStateProvisioned,
+		},
+		{
+			Slug:    "logs-1234567890",
+			Name:    "logs",
+			Plan:    bonsai.Plan{Slug: "standard-sm"},
+			Space:   bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+			Release: bonsai.Release{Slug: "opensearch-2.6.0-mt"},
+			State:   bonsai.ClusterStateProvisioned,
+		},
+	}
+
+	s.server = bonsaitest.NewServer(bonsaitest.WithFixtures(fixtures))
+	s.client = s.server.Client()
+
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func (s *ReconcileTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestReconcileTestSuite(t *testing.T) {
+	suite.Run(t, new(ReconcileTestSuite))
+}
+
+func (s *ReconcileTestSuite) newReconciler(opts ...reconcile.Option) *reconcile.Reconciler {
+	opts = append([]reconcile.Option{
+		reconcile.WithWaitOpts(bonsai.ClusterWaitOpts{PollInterval: time.Millisecond, Timeout: 5 * time.Second}),
+	}, opts...)
+	return reconcile.New(s.client, opts...)
+}
+
+func (s *ReconcileTestSuite) TestPlanNoChanges() {
+	plan, err := s.newReconciler().Plan(context.Background(), []reconcile.Cluster{
+		{Name: "search", Plan: "sandbox-aws-us-east-1"},
+		{Name: "logs", Space: "omc/bonsai/us-east-1/common", Release: "opensearch-2.6.0-mt"},
+	})
+	s.NoError(err)
+	s.True(plan.Empty())
+	s.False(plan.Destructive())
+	s.Equal("No changes. Clusters are up-to-date.\n", plan.String())
+}
+
+func (s *ReconcileTestSuite) TestPlanDiff() {
+	plan, err := s.newReconciler().Plan(context.Background(), []reconcile.Cluster{
+		{Name: "metrics", Plan: "sandbox-aws-us-east-1", Space: "omc/bonsai/us-east-1/common"},
+		{Name: "search", Plan: "standard-sm"},
+	})
+	s.NoError(err)
+	s.True(plan.Destructive())
+
+	s.Require().Len(plan.Actions, 3)
+	s.Equal(reconcile.ActionCreate, plan.Actions[0].Type)
+	s.Equal(reconcile.ActionUpdate, plan.Actions[1].Type)
+	s.Equal(
+		[]reconcile.Change{{Field: "plan", From: "sandbox-aws-us-east-1", To: "standard-sm"}},
+		plan.Actions[1].Changes,
+	)
+	s.Equal(reconcile.ActionDestroy, plan.Actions[2].Type)
+	s.Equal("logs-1234567890", plan.Actions[2].Current.Slug)
+
+	expected := strings.Join([]string{
+		`+ create cluster "metrics"`,
+		`    plan: "sandbox-aws-us-east-1"`,
+		`    space: "omc/bonsai/us-east-1/common"`,
+		`~ update cluster "search" (search-1234567890)`,
+		`    plan: "sandbox-aws-us-east-1" -> "standard-sm"`,
+		`- destroy cluster "logs" (logs-1234567890)`,
+		``,
+		`Plan: 1 to create, 1 to update, 1 to destroy.`,
+		``,
+	}, "\n")
+	s.Equal(expected, plan.String())
+}
+
+func (s *ReconcileTestSuite) TestPlanImpossibleChanges() {
+	_, err := s.newReconciler().Plan(context.Background(), []reconcile.Cluster{
+		{Name: "search", Space: "omc/bonsai/eu-west-1/common"},
+		{Name: "logs", Release: "elasticsearch-7.10.2"},
+	})
+	s.ErrorIs(err, reconcile.ErrImpossibleChange)
+
+	var changeErr reconcile.ImpossibleChangeError
+	s.True(errors.As(err, &changeErr))
+	s.Equal("search", changeErr.Name)
+	s.Equal("space", changeErr.Change.Field)
+	s.ErrorContains(err, `cluster "logs": release can't change`)
+}
+
+func (s *ReconcileTestSuite) TestPlanInvalidClusters() {
+	testCases := []struct {
+		name    string
+		desired []reconcile.Cluster
+	}{
+		{name: "empty name", desired: []reconcile.Cluster{{Plan: "standard-sm"}}},
+		{name: "duplicate name", desired: []reconcile.Cluster{{Name: "search"}, {Name: "search"}}},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			_, err := s.newReconciler().Plan(context.Background(), tc.desired)
+			s.ErrorIs(err, reconcile.ErrInvalidCluster)
+		})
+	}
+}
+
+func (s *ReconcileTestSuite) TestPlanManaged() {
+	plan, err := s.newReconciler(reconcile.WithManaged(func(c bonsai.Cluster) bool {
+		return c.Name != "logs"
+	})).Plan(context.Background(), []reconcile.Cluster{{Name: "search"}})
+	s.NoError(err)
+	s.True(plan.Empty())
+}
+
+func (s *ReconcileTestSuite) TestApplyRefusesDestroy() {
+	r := s.newReconciler()
+
+	plan, err := r.Plan(context.Background(), []reconcile.Cluster{{Name: "search"}})
+	s.NoError(err)
+
+	_, err = r.Apply(context.Background(), plan)
+	s.ErrorIs(err, reconcile.ErrDestructiveChange)
+
+	cluster, ok := s.server.Cluster("logs-1234567890")
+	s.True(ok)
+	s.Equal(bonsai.ClusterStateProvisioned, cluster.State)
+}
+
+func (s *ReconcileTestSuite) TestReconcile() {
+	r := s.newReconciler(reconcile.WithAllowDestroy(true))
+
+	plan, results, err := r.Reconcile(context.Background(), []reconcile.Cluster{
+		{Name: "metrics", Plan: "sandbox-aws-us-east-1", Space: "omc/bonsai/us-east-1/common"},
+		{Name: "search", Plan: "standard-sm"},
+	})
+	s.NoError(err)
+	s.Len(plan.Actions, 3)
+	s.Require().Len(results, 3)
+
+	s.NotEmpty(results[0].Slug)
+	s.NotEmpty(results[0].Access.Password)
+	s.Equal(bonsai.ClusterStateProvisioned, results[0].Cluster.State)
+	s.Equal(bonsai.ClusterStateProvisioned, results[1].Cluster.State)
+	s.Equal("standard-sm", results[1].Cluster.Plan.Slug)
+	s.Equal(bonsai.ClusterStateDeprovisioned, results[2].Cluster.State)
+
+	// Reconciling again is a no-op.
+	plan, err = r.Plan(context.Background(), []reconcile.Cluster{
+		{Name: "metrics", Plan: "sandbox-aws-us-east-1", Space: "omc/bonsai/us-east-1/common"},
+		{Name: "search", Plan: "standard-sm"},
+	})
+	s.NoError(err)
+	s.True(plan.Empty())
+}
+
+func (s *ReconcileTestSuite) TestApplyStopsAtFirstFailure() {
+	r := s.newReconciler(reconcile.WithWait(false))
+
+	plan, err := r.Plan(context.Background(), []reconcile.Cluster{
+		{Name: "metrics"},
+		{Name: "search", Plan: "standard-sm"},
+		{Name: "logs"},
+	})
+	s.NoError(err)
+
+	s.server.FailNext(http.MethodPost, bonsai.ClusterAPIBasePath, http.StatusPaymentRequired)
+
+	results, err := r.Apply(context.Background(), plan)
+	s.ErrorIs(err, bonsai.ErrHTTPStatusPaymentRequired)
+	s.Len(results, 1)
+
+	cluster, ok := s.server.Cluster("search-1234567890")
+	s.True(ok)
+	s.Equal("sandbox-aws-us-east-1", cluster.Plan.Slug)
+}
@@ -0,0 +1,176 @@
+package bonsai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+)
+
+// redacted replaces secrets in formatted, logged and marshaled output.
+const redacted = "REDACTED"
+
+// redactString returns redacted in place of s, unless s is empty, such
+// that missing secrets remain apparent.
+func redactString(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactURL redacts the userinfo of rawURL. URLs which can't be parsed, or
+// have no host, are redacted entirely.
+func redactURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return redacted
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(redacted, redacted)
+		} else {
+			u.User = url.User(redacted)
+		}
+	}
+	return u.String()
+}
+
+// formatRedacted formats v, a redacted value without formatting methods of
+// its own, per verb; the %#v verb is formatted as goString.
+func formatRedacted(f fmt.State, verb rune, v any, goString string) {
+	if verb == 'v' && f.Flag('#') {
+		_, _ = io.WriteString(f, goString)
+		return
+	}
+	_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), v)
+}
+
+// Reveal returns the access key itself. Otherwise, access keys are redacted
+// when formatted, logged or marshaled.
+func (k AccessKey) Reveal() string {
+	return string(k)
+}
+
+// Format implements fmt.Formatter, redacting k.
+func (k AccessKey) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, redactString(string(k)), fmt.Sprintf("bonsai.AccessKey(%q)", redactString(string(k))))
+}
+
+// LogValue implements slog.LogValuer, redacting k.
+func (k AccessKey) LogValue() slog.Value {
+	return slog.StringValue(redactString(string(k)))
+}
+
+// MarshalJSON implements json.Marshaler, redacting k.
+func (k AccessKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactString(string(k)))
+}
+
+// Reveal returns the access token itself. Otherwise, access tokens are
+// redacted when formatted, logged or marshaled.
+func (t AccessToken) Reveal() string {
+	return string(t)
+}
+
+// Format implements fmt.Formatter, redacting t.
+func (t AccessToken) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, redactString(string(t)), fmt.Sprintf("bonsai.AccessToken(%q)", redactString(string(t))))
+}
+
+// LogValue implements slog.LogValuer, redacting t.
+func (t AccessToken) LogValue() slog.Value {
+	return slog.StringValue(redactString(string(t)))
+}
+
+// MarshalJSON implements json.Marshaler, redacting t.
+func (t AccessToken) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactString(string(t)))
+}
+
+// Reveal returns the access key and token of c themselves. Otherwise,
+// credential pairs are redacted when formatted, logged or marshaled.
+func (c CredentialPair) Reveal() (accessKey, accessToken string) {
+	return c.AccessKey.Reveal(), c.AccessToken.Reveal()
+}
+
+// Format implements fmt.Formatter, redacting c.
+func (c CredentialPair) Format(f fmt.State, verb rune) {
+	// The fields of a CredentialPair redact themselves.
+	type plain CredentialPair
+	formatRedacted(f, verb, plain(c), fmt.Sprintf(
+		"bonsai.CredentialPair{AccessKey:%q, AccessToken:%q}",
+		redactString(string(c.AccessKey)), redactString(string(c.AccessToken)),
+	))
+}
+
+// LogValue implements slog.LogValuer, redacting c.
+func (c CredentialPair) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("access_key", redactString(string(c.AccessKey))),
+		slog.String("access_token", redactString(string(c.AccessToken))),
+	)
+}
+
+// RevealedClusterAccess is a ClusterAccess whose credentials are formatted
+// and marshaled as they are; see ClusterAccess.Reveal.
+type RevealedClusterAccess ClusterAccess
+
+// Reveal returns a itself, such that its credentials can be formatted or
+// marshaled; for example, to show them to the user who created the
+// Cluster. Otherwise, the credentials held by a ClusterAccess are redacted
+// when it's formatted, logged or marshaled. Its fields are unaffected.
+func (a ClusterAccess) Reveal() RevealedClusterAccess {
+	return RevealedClusterAccess(a)
+}
+
+// redact returns a copy of a with its credentials redacted.
+func (a ClusterAccess) redact() ClusterAccess {
+	a.Username = redactString(a.Username)
+	a.Password = redactString(a.Password)
+	a.URL = redactURL(a.URL)
+	return a
+}
+
+// String implements fmt.Stringer, redacting the credentials held by a.
+func (a ClusterAccess) String() string {
+	return fmt.Sprintf("%+v", RevealedClusterAccess(a.redact()))
+}
+
+// GoString implements fmt.GoStringer, redacting the credentials held by a.
+func (a ClusterAccess) GoString() string {
+	r := a.redact()
+	return fmt.Sprintf(
+		"bonsai.ClusterAccess{Host:%q, Port:%d, Scheme:%q, Username:%q, Password:%q, URL:%q}",
+		r.Host, r.Port, r.Scheme, r.Username, r.Password, r.URL,
+	)
+}
+
+// Format implements fmt.Formatter, redacting the credentials held by a.
+func (a ClusterAccess) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, RevealedClusterAccess(a.redact()), a.GoString())
+}
+
+// LogValue implements slog.LogValuer, redacting the credentials held by a.
+func (a ClusterAccess) LogValue() slog.Value {
+	r := a.redact()
+	return slog.GroupValue(
+		slog.String("host", r.Host),
+		slog.Int("port", r.Port),
+		slog.String("scheme", r.Scheme),
+		slog.String("user", r.Username),
+		slog.String("pass", r.Password),
+		slog.String("url", r.URL),
+	)
+}
+
+// MarshalJSON implements json.Marshaler, redacting the credentials held by
+// a. Marshal a.Reveal() to include them.
+func (a ClusterAccess) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RevealedClusterAccess(a.redact()))
+}
@@ -52,8 +52,6 @@ func (o releaseListOptions) values() url.Values {
 
 // list returns a list of Releases for the page specified,
 // by performing a GET request against [spaceAPIBasePath].
-//
-// Note: Pagination is not currently supported.
 func (c *ReleaseClient) list(ctx context.Context, opt releaseListOptions) ([]Release, *Response, error) {
 	var (
 		req    *http.Request
@@ -81,7 +79,7 @@ func (c *ReleaseClient) list(ctx context.Context, opt releaseListOptions) ([]Rel
 		return results.Releases, nil, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Release.List"}), req)
 	if err != nil {
 		return results.Releases, resp, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -93,36 +91,17 @@ func (c *ReleaseClient) list(ctx context.Context, opt releaseListOptions) ([]Rel
 	return results.Releases, resp, nil
 }
 
-// All lists all Releases from the Releases API.
-func (c *ReleaseClient) All(ctx context.Context) ([]Release, error) {
-	var (
-		err  error
-		resp *Response
-	)
-
-	allResults := make([]Release, 0, defaultListResultSize)
-	// No pagination support as yet, but support it for future use
-
-	err = c.all(ctx, newEmptyListOpts(), func(opt listOpts) (*Response, error) {
-		var listResults []Release
-
-		listResults, resp, err = c.list(ctx, releaseListOptions{listOpts: opt})
-		if err != nil {
-			return resp, fmt.Errorf("client.list failed: %w", err)
-		}
-
-		allResults = append(allResults, listResults...)
-		if len(allResults) >= resp.TotalRecords {
-			resp.MarkPaginationComplete()
-		}
-		return resp, err
+// Iter returns a Pager over the Releases from the Releases API, starting from the
+// page specified by page.
+func (c *ReleaseClient) Iter(page PageOpts) *Pager[Release] {
+	return newPager(c.Client, page, func(ctx context.Context, o listOpts) ([]Release, *Response, error) {
+		return c.list(ctx, releaseListOptions{listOpts: o})
 	})
+}
 
-	if err != nil {
-		return allResults, fmt.Errorf("client.all failed: %w", err)
-	}
-
-	return allResults, err
+// All lists all Releases from the Releases API.
+func (c *ReleaseClient) All(ctx context.Context) ([]Release, error) {
+	return c.Iter(PageOpts{}).All(ctx)
 }
 
 // GetBySlug gets a Release from the Releases API by its slug.
@@ -149,7 +128,7 @@ func (c *ReleaseClient) GetBySlug(ctx context.Context, slug string) (Release, er
 		return result, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Release.GetBySlug"}), req)
 	if err != nil {
 		return result, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -0,0 +1,173 @@
+package bonsai
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Client retry policy defaults.
+const (
+	// DefaultRetryMaxAttempts is the default maximum number of attempts,
+	// including the first, made for a single request.
+	DefaultRetryMaxAttempts = 5
+	// DefaultRetryBaseDelay is the default delay before the first retry,
+	// when the server hasn't requested a specific delay.
+	DefaultRetryBaseDelay = 1 * time.Second
+	// DefaultRetryMaxDelay is the default upper bound on the computed
+	// exponential backoff delay.
+	DefaultRetryMaxDelay = 30 * time.Second
+)
+
+// RetryPolicy configures if, when, and how often Client.Do retries a
+// failed request.
+//
+// Requests rejected with http.StatusTooManyRequests (429) are retried
+// regardless of method, as the API didn't act on them. All other retryable
+// failures - the configured RetryStatuses and network errors - are only
+// retried for idempotent methods (GET, HEAD, OPTIONS, PUT, and DELETE).
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first,
+	// made for a single request. Values below 1 are treated as 1, which
+	// disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. Each subsequent retry
+	// doubles the delay, up to MaxDelay. A random jitter of up to half of
+	// the delay is subtracted from each delay.
+	//
+	// The delay requested by a response's Retry-After header always
+	// takes precedence.
+	BaseDelay time.Duration
+	// MaxDelay is the upper bound on the computed backoff delay.
+	MaxDelay time.Duration
+	// RetryStatuses holds the HTTP status codes, in addition to
+	// http.StatusTooManyRequests, that may be retried.
+	// For example: http.StatusBadGateway, http.StatusServiceUnavailable and
+	// http.StatusGatewayTimeout.
+	RetryStatuses []int
+	// RetryNetworkErrors enables retrying requests that failed before a
+	// response was received.
+	RetryNetworkErrors bool
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used by a Client which hasn't
+// been configured with WithRetryPolicy; it only retries rate-limited
+// requests.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+	}
+}
+
+// WithRetryPolicy configures the policy used to retry failed requests.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = p
+	}
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	return max(p.MaxAttempts, 1)
+}
+
+// retryable reports whether a request made with method, having resulted in
+// resp and err, may be attempted again.
+func (p RetryPolicy) retryable(ctx context.Context, method string, resp *Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if errors.Is(err, ErrHTTPStatusTooManyRequests) {
+		return true
+	}
+
+	if !idempotentMethod(method) {
+		return false
+	}
+
+	if resp != nil && resp.httpResponse != nil {
+		return slices.Contains(p.RetryStatuses, resp.StatusCode)
+	}
+
+	// Only failures of the HTTP round trip itself are network errors;
+	// failures while awaiting the rate limiter are not.
+	urlErr := &url.Error{}
+	return p.RetryNetworkErrors && errors.As(err, &urlErr)
+}
+
+// delay returns how long to wait before making the attempt following
+// attempt, which resulted in resp.
+func (p RetryPolicy) delay(attempt int, resp *Response) time.Duration {
+	if resp != nil && resp.httpResponse != nil {
+		if d, ok := retryAfter(resp.Header, time.Now()); ok {
+			return d
+		}
+	}
+
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 {
+		d = min(d, p.MaxDelay)
+	}
+
+	//nolint:gosec // Jitter doesn't require a cryptographically secure source.
+	return d - rand.N(d/2+1)
+}
+
+// retryAfter parses the Retry-After header, which may hold either a
+// number of seconds or an HTTP-date.
+//
+// ref: https://www.rfc-editor.org/rfc/rfc9110#field.retry-after
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get(HeaderRetryAfter)
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// idempotentMethod reports whether repeating a request made with method
+// has the same effect as making it once.
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepContext blocks for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
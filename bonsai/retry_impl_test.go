@@ -0,0 +1,93 @@
+package bonsai
+
+import (
+	"net/http"
+	"time"
+)
+
+func (s *ClientImplTestSuite) TestRetryAfter() {
+	now := time.Date(2024, 5, 15, 1, 8, 32, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		received string
+		expect   time.Duration
+		ok       bool
+	}{
+		{
+			name:     "delay in seconds",
+			received: "120",
+			expect:   2 * time.Minute,
+			ok:       true,
+		},
+		{
+			name:     "http-date",
+			received: now.Add(90 * time.Second).Format(http.TimeFormat),
+			expect:   90 * time.Second,
+			ok:       true,
+		},
+		{
+			name:     "http-date in the past",
+			received: now.Add(-time.Minute).Format(http.TimeFormat),
+			expect:   0,
+			ok:       true,
+		},
+		{
+			name:     "absent",
+			received: "",
+			expect:   0,
+			ok:       false,
+		},
+		{
+			name:     "malformed",
+			received: "soon",
+			expect:   0,
+			ok:       false,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			h := http.Header{}
+			if tc.received != "" {
+				h.Set(HeaderRetryAfter, tc.received)
+			}
+
+			d, ok := retryAfter(h, now)
+			s.Equal(tc.ok, ok)
+			s.Equal(tc.expect, d)
+		})
+	}
+}
+
+func (s *ClientImplTestSuite) TestRetryPolicyDelay() {
+	p := RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+	}
+
+	testCases := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{attempt: 1, ceiling: 100 * time.Millisecond},
+		{attempt: 2, ceiling: 200 * time.Millisecond},
+		{attempt: 3, ceiling: 400 * time.Millisecond},
+		{attempt: 5, ceiling: time.Second},
+		{attempt: 50, ceiling: time.Second},
+	}
+
+	for _, tc := range testCases {
+		for range 20 {
+			d := p.delay(tc.attempt, nil)
+			s.LessOrEqual(d, tc.ceiling, "attempt %d delay is capped", tc.attempt)
+			s.GreaterOrEqual(d, tc.ceiling/2, "attempt %d jitter is at most half the delay", tc.attempt)
+		}
+	}
+}
+
+func (s *ClientImplTestSuite) TestClientDefaultRetryPolicy() {
+	c := NewClient()
+	s.Equal(DefaultRetryPolicy(), c.retryPolicy)
+	s.Equal([]int(nil), c.retryPolicy.RetryStatuses, "only rate-limited requests are retried by default")
+}
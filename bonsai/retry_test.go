@@ -0,0 +1,199 @@
+package bonsai_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// newRetryingClient returns a Client against the suite's server, which
+// retries quickly.
+func (s *ClientMockTestSuite) newRetryingClient(policy bonsai.RetryPolicy) *bonsai.Client {
+	return bonsai.NewClient(
+		bonsai.WithEndpoint(s.server.URL),
+		bonsai.WithRetryPolicy(policy),
+	)
+}
+
+func (s *ClientMockTestSuite) TestClient_RetryPolicy() {
+	policy := bonsai.RetryPolicy{
+		MaxAttempts:        3,
+		BaseDelay:          time.Millisecond,
+		MaxDelay:           5 * time.Millisecond,
+		RetryStatuses:      []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+		RetryNetworkErrors: true,
+	}
+	client := s.newRetryingClient(policy)
+
+	// failFirst serves status for the first n requests to p, followed by
+	// a successful JSON response.
+	failFirst := func(method, p string, status, n int) *int {
+		calls := 0
+		s.serveMux.MethodFunc(method, p, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+
+			body, err := io.ReadAll(r.Body)
+			s.NoError(err, "read request body")
+			if r.ContentLength > 0 {
+				s.Equal(`{"name":"retried"}`, string(body), "request body is replayed on each attempt")
+			}
+
+			w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+			if calls <= n {
+				w.Header().Set(bonsai.HeaderRetryAfter, "0")
+				w.WriteHeader(status)
+				_, err = fmt.Fprintf(w, `{"errors": ["failed attempt %d"], "status": %d}`, calls, status)
+				s.NoError(err, "wrote error response")
+				return
+			}
+			_, err = fmt.Fprint(w, `{"message": "ok"}`)
+			s.NoError(err, "wrote success response")
+		})
+		return &calls
+	}
+
+	do := func(method, p string) (*bonsai.Response, error) {
+		var body io.Reader
+		if method != http.MethodGet {
+			body = bytes.NewBufferString(`{"name":"retried"}`)
+		}
+		req, err := client.NewRequest(context.Background(), method, p, body)
+		s.NoError(err, "request creation returns no error")
+		return client.Do(context.Background(), req)
+	}
+
+	s.Run("retries configured status on idempotent method", func() {
+		calls := failFirst(http.MethodGet, "/retry/get-503", http.StatusServiceUnavailable, 2)
+
+		resp, err := do(http.MethodGet, "/retry/get-503")
+		s.NoError(err, "succeeds after retries")
+		s.Equal(3, *calls)
+		s.Equal(3, resp.Attempts)
+	})
+
+	s.Run("doesn't retry configured status on non-idempotent method", func() {
+		calls := failFirst(http.MethodPost, "/retry/post-502", http.StatusBadGateway, 1)
+
+		resp, err := do(http.MethodPost, "/retry/post-502")
+		s.Error(err, "fails without retrying")
+		s.Equal(1, *calls)
+		s.Equal(1, resp.Attempts)
+		s.Equal(http.StatusBadGateway, resp.StatusCode)
+	})
+
+	s.Run("retries rate limited non-idempotent method", func() {
+		calls := failFirst(http.MethodPost, "/retry/post-429", http.StatusTooManyRequests, 1)
+
+		resp, err := do(http.MethodPost, "/retry/post-429")
+		s.NoError(err, "succeeds after retry")
+		s.Equal(2, *calls)
+		s.Equal(2, resp.Attempts)
+	})
+
+	s.Run("stops after max attempts", func() {
+		calls := failFirst(http.MethodPut, "/retry/put-429", http.StatusTooManyRequests, 10)
+
+		resp, err := do(http.MethodPut, "/retry/put-429")
+		s.ErrorIs(err, bonsai.ErrHTTPStatusTooManyRequests)
+		s.Equal(policy.MaxAttempts, *calls)
+		s.Equal(policy.MaxAttempts, resp.Attempts)
+	})
+
+	s.Run("doesn't retry client errors", func() {
+		calls := failFirst(http.MethodDelete, "/retry/delete-422", http.StatusUnprocessableEntity, 1)
+
+		_, err := do(http.MethodDelete, "/retry/delete-422")
+		s.ErrorIs(err, bonsai.ErrHTTPStatusUnprocessableEntity)
+		s.Equal(1, *calls)
+	})
+}
+
+func (s *ClientMockTestSuite) TestClient_RetryPolicyRespectsContext() {
+	const p = "/retry/context"
+
+	calls := 0
+	s.serveMux.Get(p, func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	client := s.newRetryingClient(bonsai.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Hour,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req, err := client.NewRequest(ctx, http.MethodGet, p, nil)
+	s.NoError(err, "request creation returns no error")
+
+	_, err = client.Do(ctx, req)
+	s.ErrorIs(err, context.DeadlineExceeded, "sleep between attempts is interrupted")
+	s.ErrorIs(err, bonsai.ErrHTTPStatusTooManyRequests, "last response error is retained")
+	s.Equal(1, calls)
+}
+
+// flakyTransport fails the first failures round trips with a network error.
+type flakyTransport struct {
+	failures int
+	calls    int
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	if t.calls <= t.failures {
+		return nil, errors.New("connection reset by peer")
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (s *ClientMockTestSuite) TestClient_RetryPolicyNetworkErrors() {
+	const p = "/retry/network"
+
+	s.serveMux.Get(p, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name          string
+		retryNetwork  bool
+		expectErr     bool
+		expectedCalls int
+	}{
+		{name: "retried when enabled", retryNetwork: true, expectErr: false, expectedCalls: 2},
+		{name: "not retried when disabled", retryNetwork: false, expectErr: true, expectedCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			transport := &flakyTransport{failures: 1}
+			client := bonsai.NewClient(
+				bonsai.WithEndpoint(s.server.URL),
+				bonsai.WithHTTPTransport(transport),
+				bonsai.WithRetryPolicy(bonsai.RetryPolicy{
+					MaxAttempts:        2,
+					BaseDelay:          time.Millisecond,
+					RetryNetworkErrors: tc.retryNetwork,
+				}),
+			)
+
+			req, err := client.NewRequest(context.Background(), http.MethodGet, p, nil)
+			s.NoError(err, "request creation returns no error")
+
+			_, err = client.Do(context.Background(), req)
+			if tc.expectErr {
+				s.Error(err)
+			} else {
+				s.NoError(err)
+			}
+			s.Equal(tc.expectedCalls, transport.calls)
+		})
+	}
+}
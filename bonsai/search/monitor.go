@@ -0,0 +1,197 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HealthStatus summarizes the health of a cluster.
+type HealthStatus string
+
+const (
+	// HealthGreen indicates that all shards are allocated.
+	HealthGreen HealthStatus = "green"
+	// HealthYellow indicates that all primary shards are allocated, but
+	// some replicas aren't.
+	HealthYellow HealthStatus = "yellow"
+	// HealthRed indicates that some primary shards aren't allocated.
+	HealthRed HealthStatus = "red"
+)
+
+// Health holds the health of a cluster, as reported by the cluster health
+// API.
+type Health struct {
+	ClusterName         string       `json:"cluster_name"`
+	Status              HealthStatus `json:"status"`
+	TimedOut            bool         `json:"timed_out"`
+	NumberOfNodes       int          `json:"number_of_nodes"`
+	NumberOfDataNodes   int          `json:"number_of_data_nodes"`
+	ActivePrimaryShards int          `json:"active_primary_shards"`
+	ActiveShards        int          `json:"active_shards"`
+	RelocatingShards    int          `json:"relocating_shards"`
+	InitializingShards  int          `json:"initializing_shards"`
+	UnassignedShards    int          `json:"unassigned_shards"`
+	// ActiveShardsPercent is the percentage of shards which are active.
+	ActiveShardsPercent float64 `json:"active_shards_percent_as_number"`
+}
+
+// HealthOpts are options for Health requests.
+type HealthOpts struct {
+	// Indices limits the health reported to that of the named indices, or
+	// index patterns.
+	Indices []string
+	// WaitForStatus waits until the cluster reaches the given status, or
+	// better, or until Timeout elapses; Health.TimedOut reports the latter.
+	WaitForStatus HealthStatus
+	// Timeout bounds the time the search engine spends waiting for its
+	// elected master node, and for WaitForStatus.
+	Timeout time.Duration
+}
+
+// Health returns the health of the cluster.
+func (c *Client) Health(ctx context.Context, opt HealthOpts) (Health, error) {
+	path := "/_cluster/health"
+	if len(opt.Indices) > 0 {
+		path += "/" + joinIndices(opt.Indices)
+	}
+
+	query := url.Values{}
+	if opt.WaitForStatus != "" {
+		query.Set("wait_for_status", string(opt.WaitForStatus))
+	}
+	if opt.Timeout > 0 {
+		timeout := formatTimeout(opt.Timeout)
+		query.Set("timeout", timeout)
+		query.Set(c.engine.clusterManagerTimeoutParam(), timeout)
+	}
+
+	var health Health
+	if err := c.get(ctx, path, query, &health); err != nil {
+		return Health{}, fmt.Errorf("getting cluster health: %w", err)
+	}
+	return health, nil
+}
+
+// Index summarizes an index, as listed by the cat indices API.
+//
+// The counts and sizes of closed indices aren't reported, and are zero.
+type Index struct {
+	Name   string       `json:"index"`
+	UUID   string       `json:"uuid"`
+	Health HealthStatus `json:"health"`
+	// Status is "open" or "close".
+	Status string `json:"status"`
+	// Primaries is the number of primary shards.
+	Primaries int `json:"pri,string"`
+	// Replicas is the number of replicas of each primary shard.
+	Replicas    int   `json:"rep,string"`
+	DocsCount   int64 `json:"docs.count,string"`
+	DocsDeleted int64 `json:"docs.deleted,string"`
+	// StoreSizeBytes is the size of all shards of the index, in bytes.
+	StoreSizeBytes int64 `json:"store.size,string"`
+	// PrimaryStoreSizeBytes is the size of the primary shards of the
+	// index, in bytes.
+	PrimaryStoreSizeBytes int64 `json:"pri.store.size,string"`
+}
+
+// Indices lists the cluster's indices, or those matching the given names
+// or index patterns.
+func (c *Client) Indices(ctx context.Context, indices ...string) ([]Index, error) {
+	path := "/_cat/indices"
+	if len(indices) > 0 {
+		path += "/" + joinIndices(indices)
+	}
+
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("bytes", "b")
+
+	var result []Index
+	if err := c.get(ctx, path, query, &result); err != nil {
+		return nil, fmt.Errorf("listing indices: %w", err)
+	}
+	return result, nil
+}
+
+// DocsStats holds document counts.
+type DocsStats struct {
+	Count   int64 `json:"count"`
+	Deleted int64 `json:"deleted"`
+}
+
+// StoreStats holds on-disk sizes.
+type StoreStats struct {
+	SizeInBytes int64 `json:"size_in_bytes"`
+}
+
+// IndexingStats holds indexing operation counts and times.
+type IndexingStats struct {
+	IndexTotal        int64 `json:"index_total"`
+	IndexTimeInMillis int64 `json:"index_time_in_millis"`
+	IndexFailed       int64 `json:"index_failed"`
+	DeleteTotal       int64 `json:"delete_total"`
+}
+
+// SearchStats holds search operation counts and times.
+type SearchStats struct {
+	QueryTotal        int64 `json:"query_total"`
+	QueryTimeInMillis int64 `json:"query_time_in_millis"`
+	FetchTotal        int64 `json:"fetch_total"`
+	FetchTimeInMillis int64 `json:"fetch_time_in_millis"`
+}
+
+// Stats holds *some* of the statistics reported by the index stats API.
+type Stats struct {
+	Docs     DocsStats     `json:"docs"`
+	Store    StoreStats    `json:"store"`
+	Indexing IndexingStats `json:"indexing"`
+	Search   SearchStats   `json:"search"`
+}
+
+// IndexStatsGroup holds statistics of the primary shards, and of all
+// shards, of one or more indices.
+type IndexStatsGroup struct {
+	Primaries Stats `json:"primaries"`
+	Total     Stats `json:"total"`
+}
+
+// IndexStats holds the statistics of the indices requested from the index
+// stats API, keyed by index name, along with their sum.
+type IndexStats struct {
+	All     IndexStatsGroup            `json:"_all"`
+	Indices map[string]IndexStatsGroup `json:"indices"`
+}
+
+// IndexStats returns the statistics of the cluster's indices, or those
+// matching the given names or index patterns.
+func (c *Client) IndexStats(ctx context.Context, indices ...string) (IndexStats, error) {
+	path := "/_stats"
+	if len(indices) > 0 {
+		path = "/" + joinIndices(indices) + path
+	}
+
+	var stats IndexStats
+	if err := c.get(ctx, path, url.Values{}, &stats); err != nil {
+		return IndexStats{}, fmt.Errorf("getting index stats: %w", err)
+	}
+	return stats, nil
+}
+
+// joinIndices joins index names, or patterns, into a single path segment.
+func joinIndices(indices []string) string {
+	escaped := make([]string, len(indices))
+	for i, index := range indices {
+		escaped[i] = url.PathEscape(index)
+	}
+	return strings.Join(escaped, ",")
+}
+
+// formatTimeout formats d in the time units understood by search engines,
+// rounding up to the nearest millisecond.
+func formatTimeout(d time.Duration) string {
+	ms := (d + time.Millisecond - 1) / time.Millisecond
+	return fmt.Sprintf("%dms", ms)
+}
@@ -0,0 +1,237 @@
+// Package search makes monitoring requests directly to the search engine
+// of a Bonsai cluster, rather than to the Bonsai API.
+//
+// The statistics held by a [bonsai.Cluster] are only updated every 10-15
+// minutes. A Client reports the cluster's health, its indices and their
+// statistics as they are now, routing each request per the
+// [bonsai.Release.ServiceType] of the cluster: Elasticsearch or OpenSearch.
+//
+//	c, err := search.New(cluster.Access, cluster.Release)
+//	if err != nil {
+//		...
+//	}
+//	health, err := c.Health(ctx, search.HealthOpts{})
+//
+// As the API only returns a cluster's credentials on creation, the Access
+// of a Cluster retrieved later must have its credentials set first; for
+// example, from a [bonsai.CredentialRecord].
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// ErrUnsupportedEngine is returned by New for releases of search engines
+// which a Client can't make requests to.
+var ErrUnsupportedEngine = errors.New("unsupported search engine")
+
+// maxErrorBodyLength is the maximum length of the raw response body held
+// by an Error.
+const maxErrorBodyLength = 512
+
+// Engine identifies the search engine run by a cluster, per its
+// [bonsai.Release.ServiceType].
+type Engine string
+
+const (
+	EngineElasticsearch Engine = "elasticsearch"
+	EngineOpenSearch    Engine = "opensearch"
+)
+
+// clusterManagerTimeoutParam returns the name of the query parameter which
+// bounds the time spent waiting for the cluster's elected master node;
+// OpenSearch deprecated Elasticsearch's name for it.
+func (e Engine) clusterManagerTimeoutParam() string {
+	if e == EngineOpenSearch {
+		return "cluster_manager_timeout"
+	}
+	return "master_timeout"
+}
+
+// engineOf returns the Engine run by clusters deployed with release.
+func engineOf(release bonsai.Release) (Engine, error) {
+	switch engine := Engine(strings.ToLower(release.ServiceType)); engine {
+	case EngineElasticsearch, EngineOpenSearch:
+		return engine, nil
+	case "":
+		return "", fmt.Errorf(
+			"%w: release %q has no service type; expand it with ReleaseClient.GetBySlug",
+			ErrUnsupportedEngine, release.Slug,
+		)
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedEngine, release.ServiceType)
+	}
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient configures the http.Client used to make requests, in
+// place of that returned by [bonsai.ClusterAccess.HTTPClient]. The client
+// must authenticate requests itself; for example, it may be one returned
+// by HTTPClient, with a Timeout set.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// Client makes requests to the search engine of a single cluster. It's
+// safe for concurrent use.
+type Client struct {
+	engine     Engine
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+// New returns a Client for the cluster reached via access, which runs the
+// search engine of release.
+func New(access bonsai.ClusterAccess, release bonsai.Release, opts ...Option) (*Client, error) {
+	engine, err := engineOf(release)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := access.BaseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		engine:     engine,
+		baseURL:    baseURL,
+		httpClient: access.HTTPClient(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Engine returns the search engine the Client makes requests to.
+func (c *Client) Engine() Engine {
+	return c.engine
+}
+
+// get makes a GET request to path, with query parameters, decoding the
+// JSON response body into v.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) error {
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", u.Path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("GET %s: reading response body: %w", u.Path, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newError(req, resp, body)
+	}
+
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("GET %s: decoding response body: %w", u.Path, err)
+	}
+	return nil
+}
+
+// Error describes an error response received from the search engine.
+//
+// Error matches the bonsai.ErrHTTPStatus* errors corresponding to its
+// Status via errors.Is.
+type Error struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the URL path of the request.
+	Path string
+	// Status is the HTTP status code of the response.
+	Status int
+	// Type is the type of the error reported by the search engine, such as
+	// "index_not_found_exception", if any.
+	Type string
+	// Reason describes the error reported by the search engine, if any.
+	Reason string
+	// Body holds the raw response body, truncated to a reasonable length.
+	Body string
+}
+
+func newError(req *http.Request, resp *http.Response, body []byte) Error {
+	e := Error{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Status: resp.StatusCode,
+		Body:   truncate(string(body), maxErrorBodyLength),
+	}
+
+	// The error may be an object, or, from older engines, a string;
+	// otherwise, the raw body is retained.
+	var parsed struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &parsed) != nil || len(parsed.Error) == 0 {
+		return e
+	}
+
+	var detail struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	}
+	if json.Unmarshal(parsed.Error, &detail) == nil {
+		e.Type, e.Reason = detail.Type, detail.Reason
+	} else {
+		_ = json.Unmarshal(parsed.Error, &e.Reason)
+	}
+
+	return e
+}
+
+func (e Error) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s: %d %s", e.Method, e.Path, e.Status, http.StatusText(e.Status))
+
+	switch {
+	case e.Type != "" && e.Reason != "":
+		fmt.Fprintf(&b, ": %s: %s", e.Type, e.Reason)
+	case e.Type != "" || e.Reason != "":
+		fmt.Fprintf(&b, ": %s%s", e.Type, e.Reason)
+	case strings.TrimSpace(e.Body) != "":
+		fmt.Fprintf(&b, ": %s", strings.TrimSpace(e.Body))
+	}
+
+	return b.String()
+}
+
+func (e Error) Is(target error) bool {
+	return bonsai.ResponseError{Status: e.Status}.Is(target)
+}
+
+// truncate shortens s to at most n bytes, without splitting a UTF-8
+// encoded rune, marking it as truncated with an ellipsis.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "") + "…"
+}
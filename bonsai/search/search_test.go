@@ -0,0 +1,333 @@
+package search_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/search"
+)
+
+var (
+	elasticsearch = bonsai.Release{Slug: "elasticsearch-7.10.2", ServiceType: "elasticsearch"}
+	opensearch    = bonsai.Release{Slug: "opensearch-2.6.0-mt", ServiceType: "opensearch"}
+)
+
+type SearchTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all HTTP request tests
+	suite.Suite
+
+	// serveMux is the request multiplexer of the fake search engine,
+	// recreated for each test
+	serveMux *chi.Mux
+	server   *httptest.Server
+	// access reaches server, with valid credentials
+	access bonsai.ClusterAccess
+}
+
+func (s *SearchTestSuite) SetupTest() {
+	s.serveMux = chi.NewRouter()
+	s.serveMux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user, pass, ok := r.BasicAuth(); !ok || user != "user1234" || pass != "secret1234" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":{"type":"security_exception","reason":"missing authentication"},"status":401}`))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	s.server = httptest.NewServer(s.serveMux)
+
+	s.Assertions = require.New(s.T())
+
+	u, err := url.Parse(s.server.URL)
+	s.NoError(err)
+	port, err := strconv.Atoi(u.Port())
+	s.NoError(err)
+
+	s.access = bonsai.ClusterAccess{
+		Host:     u.Hostname(),
+		Port:     port,
+		Scheme:   "http",
+		Username: "user1234",
+		Password: "secret1234",
+	}
+}
+
+func (s *SearchTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
+
+func (s *SearchTestSuite) TestNew() {
+	c, err := search.New(s.access, opensearch)
+	s.NoError(err)
+	s.Equal(search.EngineOpenSearch, c.Engine())
+
+	c, err = search.New(s.access, bonsai.Release{ServiceType: "Elasticsearch"})
+	s.NoError(err)
+	s.Equal(search.EngineElasticsearch, c.Engine())
+
+	_, err = search.New(s.access, bonsai.Release{Slug: "solr-9", ServiceType: "solr"})
+	s.ErrorIs(err, search.ErrUnsupportedEngine)
+
+	_, err = search.New(s.access, bonsai.Release{Slug: "opensearch-2.6.0-mt"})
+	s.ErrorIs(err, search.ErrUnsupportedEngine, "release stubs have no service type")
+
+	_, err = search.New(bonsai.ClusterAccess{}, opensearch)
+	s.ErrorIs(err, bonsai.ErrIncompleteClusterAccess)
+}
+
+func (s *SearchTestSuite) TestHealth() {
+	var queries []url.Values
+	s.serveMux.Get("/_cluster/health", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		_, _ = w.Write([]byte(`{
+			"cluster_name": "example",
+			"status": "yellow",
+			"timed_out": false,
+			"number_of_nodes": 3,
+			"number_of_data_nodes": 3,
+			"active_primary_shards": 2,
+			"active_shards": 3,
+			"relocating_shards": 0,
+			"initializing_shards": 0,
+			"unassigned_shards": 1,
+			"active_shards_percent_as_number": 75.0
+		}`))
+	})
+
+	opts := search.HealthOpts{WaitForStatus: search.HealthYellow, Timeout: 1500 * time.Microsecond}
+
+	for _, release := range []bonsai.Release{elasticsearch, opensearch} {
+		c, err := search.New(s.access, release)
+		s.NoError(err)
+
+		health, err := c.Health(context.Background(), opts)
+		s.NoError(err)
+		s.Equal(search.Health{
+			ClusterName:         "example",
+			Status:              search.HealthYellow,
+			NumberOfNodes:       3,
+			NumberOfDataNodes:   3,
+			ActivePrimaryShards: 2,
+			ActiveShards:        3,
+			UnassignedShards:    1,
+			ActiveShardsPercent: 75,
+		}, health)
+	}
+
+	s.Equal([]url.Values{
+		{"wait_for_status": {"yellow"}, "timeout": {"2ms"}, "master_timeout": {"2ms"}},
+		{"wait_for_status": {"yellow"}, "timeout": {"2ms"}, "cluster_manager_timeout": {"2ms"}},
+	}, queries, "the cluster manager timeout is named per engine")
+}
+
+func (s *SearchTestSuite) TestHealth_Indices() {
+	s.serveMux.Get("/_cluster/health/{indices}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"cluster_name": "` + chi.URLParam(r, "indices") + `", "status": "green"}`))
+	})
+
+	c, err := search.New(s.access, opensearch)
+	s.NoError(err)
+
+	health, err := c.Health(context.Background(), search.HealthOpts{Indices: []string{"logs-*", "products"}})
+	s.NoError(err)
+	s.Equal("logs-*,products", health.ClusterName)
+	s.Equal(search.HealthGreen, health.Status)
+}
+
+func (s *SearchTestSuite) TestIndices() {
+	s.serveMux.Get("/_cat/indices", func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("json", r.URL.Query().Get("format"))
+		s.Equal("b", r.URL.Query().Get("bytes"))
+		_, _ = w.Write([]byte(`[
+			{
+				"health": "green",
+				"status": "open",
+				"index": "products",
+				"uuid": "lkJ2hAVyQbyjEPtT9CFuaQ",
+				"pri": "1",
+				"rep": "1",
+				"docs.count": "1200",
+				"docs.deleted": "3",
+				"store.size": "208000",
+				"pri.store.size": "104000"
+			},
+			{
+				"health": "red",
+				"status": "close",
+				"index": "archive",
+				"uuid": "fNg3nJ5bSUeWTXJw-c0J7A",
+				"pri": "1",
+				"rep": "1",
+				"docs.count": null,
+				"docs.deleted": null,
+				"store.size": null,
+				"pri.store.size": null
+			}
+		]`))
+	})
+
+	c, err := search.New(s.access, elasticsearch)
+	s.NoError(err)
+
+	indices, err := c.Indices(context.Background())
+	s.NoError(err)
+	s.Equal([]search.Index{
+		{
+			Name:                  "products",
+			UUID:                  "lkJ2hAVyQbyjEPtT9CFuaQ",
+			Health:                search.HealthGreen,
+			Status:                "open",
+			Primaries:             1,
+			Replicas:              1,
+			DocsCount:             1200,
+			DocsDeleted:           3,
+			StoreSizeBytes:        208000,
+			PrimaryStoreSizeBytes: 104000,
+		},
+		{
+			Name:      "archive",
+			UUID:      "fNg3nJ5bSUeWTXJw-c0J7A",
+			Health:    search.HealthRed,
+			Status:    "close",
+			Primaries: 1,
+			Replicas:  1,
+		},
+	}, indices)
+}
+
+func (s *SearchTestSuite) TestIndexStats() {
+	const statsResponse = `{
+		"_shards": {"total": 2, "successful": 2, "failed": 0},
+		"_all": {
+			"primaries": {"docs": {"count": 1200, "deleted": 3}, "store": {"size_in_bytes": 104000}},
+			"total": {"docs": {"count": 2400, "deleted": 6}, "store": {"size_in_bytes": 208000}}
+		},
+		"indices": {
+			"products": {
+				"uuid": "lkJ2hAVyQbyjEPtT9CFuaQ",
+				"primaries": {
+					"docs": {"count": 1200, "deleted": 3},
+					"store": {"size_in_bytes": 104000},
+					"indexing": {"index_total": 1203, "index_time_in_millis": 420},
+					"search": {"query_total": 52, "query_time_in_millis": 31}
+				},
+				"total": {"docs": {"count": 2400, "deleted": 6}, "store": {"size_in_bytes": 208000}}
+			}
+		}
+	}`
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(statsResponse))
+	}
+	s.serveMux.Get("/_stats", handler)
+	s.serveMux.Get("/{indices}/_stats", handler)
+
+	c, err := search.New(s.access, opensearch)
+	s.NoError(err)
+
+	stats, err := c.IndexStats(context.Background())
+	s.NoError(err)
+	s.Equal(int64(2400), stats.All.Total.Docs.Count)
+	s.Equal(int64(104000), stats.All.Primaries.Store.SizeInBytes)
+	s.Equal(search.Stats{
+		Docs:     search.DocsStats{Count: 1200, Deleted: 3},
+		Store:    search.StoreStats{SizeInBytes: 104000},
+		Indexing: search.IndexingStats{IndexTotal: 1203, IndexTimeInMillis: 420},
+		Search:   search.SearchStats{QueryTotal: 52, QueryTimeInMillis: 31},
+	}, stats.Indices["products"].Primaries)
+
+	_, err = c.IndexStats(context.Background(), "products", "logs-*")
+	s.NoError(err)
+
+	s.Equal([]string{"/_stats", "/products,logs-*/_stats"}, paths)
+}
+
+func (s *SearchTestSuite) TestErrors() {
+	s.serveMux.Get("/{indices}/_stats", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{
+			"error": {
+				"root_cause": [{"type": "index_not_found_exception", "reason": "no such index [missing]"}],
+				"type": "index_not_found_exception",
+				"reason": "no such index [missing]"
+			},
+			"status": 404
+		}`))
+	})
+	s.serveMux.Get("/_cat/indices", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`upstream unavailable`))
+	})
+
+	c, err := search.New(s.access, elasticsearch)
+	s.NoError(err)
+
+	_, err = c.IndexStats(context.Background(), "missing")
+	s.ErrorIs(err, bonsai.ErrHTTPStatusNotFound)
+	var searchErr search.Error
+	s.ErrorAs(err, &searchErr)
+	s.Equal("index_not_found_exception", searchErr.Type)
+	s.Equal("no such index [missing]", searchErr.Reason)
+	s.EqualError(err,
+		"getting index stats: GET /missing/_stats: 404 Not Found: index_not_found_exception: no such index [missing]")
+
+	_, err = c.Indices(context.Background())
+	s.ErrorIs(err, bonsai.ErrHTTPStatusServerError)
+	s.ErrorContains(err, "503 Service Unavailable: upstream unavailable")
+
+	access := s.access
+	access.Password = "wrong"
+	c, err = search.New(access, elasticsearch)
+	s.NoError(err)
+
+	_, err = c.Health(context.Background(), search.HealthOpts{})
+	s.ErrorIs(err, bonsai.ErrHTTPStatusUnauthorized)
+	s.ErrorContains(err, "security_exception: missing authentication")
+}
+
+func (s *SearchTestSuite) TestWithHTTPClient() {
+	s.serveMux.Get("/_cluster/health", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status": "green"}`))
+	})
+
+	var requests int
+	httpClient := s.access.HTTPClient()
+	base := httpClient.Transport
+	httpClient.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return base.RoundTrip(req)
+	})
+
+	c, err := search.New(s.access, opensearch, search.WithHTTPClient(httpClient))
+	s.NoError(err)
+
+	_, err = c.Health(context.Background(), search.HealthOpts{})
+	s.NoError(err)
+	s.Equal(1, requests)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
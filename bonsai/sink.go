@@ -0,0 +1,255 @@
+package bonsai
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// ErrCredentialSink is matched, via errors.Is, by errors returned by
+// ClusterClient.Create when the Client's CredentialSink fails.
+var ErrCredentialSink = errors.New("storing cluster credentials")
+
+// CredentialRecord describes the one-time credentials of a newly created
+// Cluster, as passed to a CredentialSink.
+type CredentialRecord struct {
+	// Name is the name the Cluster was created with.
+	Name string
+	// Slug is the Cluster's slug, derived from the create response's
+	// Monitor URI, if it could be.
+	Slug string
+	// Plan, Space and Release are those requested for the Cluster, if any.
+	Plan    string
+	Space   string
+	Release string
+	// Access holds the Cluster's credentials.
+	Access ClusterAccess
+	// CreatedAt is the time the create response was received.
+	CreatedAt time.Time
+}
+
+// CredentialSink persists the credentials of newly created Clusters, which
+// the API reveals only once, in response to the request creating them.
+//
+// Implementations must be safe for concurrent use.
+type CredentialSink interface {
+	StoreCredentials(ctx context.Context, record CredentialRecord) error
+}
+
+// CredentialSinkFunc adapts a function to a CredentialSink.
+type CredentialSinkFunc func(ctx context.Context, record CredentialRecord) error
+
+// StoreCredentials implements CredentialSink.
+func (f CredentialSinkFunc) StoreCredentials(ctx context.Context, record CredentialRecord) error {
+	return f(ctx, record)
+}
+
+// WithCredentialSink configures a Client to pass the credentials of each
+// Cluster it creates to sink, synchronously, before ClusterClient.Create
+// returns.
+//
+// If sink fails, Create returns its result alongside an error matching
+// ErrCredentialSink; note that the Cluster was nevertheless created.
+func WithCredentialSink(sink CredentialSink) ClientOption {
+	return func(c *Client) {
+		c.credentialSink = sink
+	}
+}
+
+// storeCredentials passes the credentials in result to the Client's
+// CredentialSink, if any.
+func (c *Client) storeCredentials(ctx context.Context, opt ClusterCreateOpts, result ClustersResultCreate) error {
+	if c.credentialSink == nil {
+		return nil
+	}
+
+	record := CredentialRecord{
+		Name:      opt.Name,
+		Slug:      ClusterSlugFromURI(result.Monitor),
+		Plan:      opt.Plan,
+		Space:     opt.Space,
+		Release:   opt.Release,
+		Access:    result.Access,
+		CreatedAt: time.Now(),
+	}
+
+	// The cluster exists regardless of whether ctx is done; its credentials
+	// must still be stored.
+	if err := c.credentialSink.StoreCredentials(context.WithoutCancel(ctx), record); err != nil {
+		return fmt.Errorf("%w for cluster %q: %w", ErrCredentialSink, opt.Name, err)
+	}
+	return nil
+}
+
+// EncryptedFileSink is a CredentialSink writing each record to its own file,
+// encrypted and authenticated with NaCl secretbox; see OpenCredentialFile.
+//
+// Files are named after the Cluster's slug (or name, if the slug is
+// unknown) and creation time, and are written atomically, readable only by
+// their owner.
+type EncryptedFileSink struct {
+	dir string
+	key [32]byte
+}
+
+// NewEncryptedFileSink returns an EncryptedFileSink writing to dir, which
+// is created if needed, with key. Keys should be generated from a
+// cryptographically secure source, and kept apart from the files.
+func NewEncryptedFileSink(dir string, key [32]byte) *EncryptedFileSink {
+	return &EncryptedFileSink{dir: dir, key: key}
+}
+
+// String implements fmt.Stringer, omitting the key.
+func (s *EncryptedFileSink) String() string {
+	return fmt.Sprintf("EncryptedFileSink{dir: %q}", s.dir)
+}
+
+// EncryptedFileExt is the extension of files written by EncryptedFileSink.
+const EncryptedFileExt = ".credentials"
+
+// secretboxNonceSize is the size of the nonce prefixing each file's
+// ciphertext.
+const secretboxNonceSize = 24
+
+// unsafeFilenameChars matches characters to be replaced in file names.
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// credentialFile is the plaintext of a credential file. It's kept distinct
+// from CredentialRecord, whose redaction must not apply here.
+type credentialFile struct {
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug,omitempty"`
+	Plan      string    `json:"plan,omitempty"`
+	Space     string    `json:"space,omitempty"`
+	Release   string    `json:"release,omitempty"`
+	Host      string    `json:"host,omitempty"`
+	Port      int       `json:"port,omitempty"`
+	Scheme    string    `json:"scheme,omitempty"`
+	Username  string    `json:"user,omitempty"`
+	Password  string    `json:"pass,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StoreCredentials implements CredentialSink.
+func (s *EncryptedFileSink) StoreCredentials(_ context.Context, record CredentialRecord) error {
+	plaintext, err := json.Marshal(credentialFile{
+		Name:      record.Name,
+		Slug:      record.Slug,
+		Plan:      record.Plan,
+		Space:     record.Space,
+		Release:   record.Release,
+		Host:      record.Access.Host,
+		Port:      record.Access.Port,
+		Scheme:    record.Access.Scheme,
+		Username:  record.Access.Username,
+		Password:  record.Access.Password,
+		URL:       record.Access.URL,
+		CreatedAt: record.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding credentials: %w", err)
+	}
+
+	var nonce [secretboxNonceSize]byte
+	if _, err = rand.Read(nonce[:]); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	sealed := secretbox.Seal(nonce[:], plaintext, &nonce, &s.key)
+
+	if err = os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("creating credentials directory: %w", err)
+	}
+
+	name := record.Slug
+	if name == "" {
+		name = record.Name
+	}
+	name = unsafeFilenameChars.ReplaceAllString(name, "_") +
+		"-" + record.CreatedAt.UTC().Format("20060102T150405.000000000Z") + EncryptedFileExt
+
+	return writeFileAtomic(filepath.Join(s.dir, name), sealed)
+}
+
+// writeFileAtomic writes data to a temporary file alongside path, readable
+// only by its owner, and renames it into place.
+func writeFileAtomic(path string, data []byte) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating credentials file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing credentials file: %w", err)
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("syncing credentials file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("closing credentials file: %w", err)
+	}
+	if err = os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("renaming credentials file: %w", err)
+	}
+	return nil
+}
+
+// ErrCredentialFileInvalid is returned by OpenCredentialFile when a file
+// can't be decrypted with the given key, or has been tampered with.
+var ErrCredentialFileInvalid = errors.New("credentials file can't be decrypted")
+
+// OpenCredentialFile decrypts a file written by EncryptedFileSink with key.
+func OpenCredentialFile(path string, key [32]byte) (CredentialRecord, error) {
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return CredentialRecord{}, fmt.Errorf("reading credentials file: %w", err)
+	}
+	if len(sealed) < secretboxNonceSize {
+		return CredentialRecord{}, ErrCredentialFileInvalid
+	}
+
+	var nonce [secretboxNonceSize]byte
+	copy(nonce[:], sealed[:secretboxNonceSize])
+
+	plaintext, ok := secretbox.Open(nil, sealed[secretboxNonceSize:], &nonce, &key)
+	if !ok {
+		return CredentialRecord{}, ErrCredentialFileInvalid
+	}
+
+	var file credentialFile
+	if err = json.Unmarshal(plaintext, &file); err != nil {
+		return CredentialRecord{}, fmt.Errorf("decoding credentials file: %w", err)
+	}
+
+	return CredentialRecord{
+		Name:    file.Name,
+		Slug:    file.Slug,
+		Plan:    file.Plan,
+		Space:   file.Space,
+		Release: file.Release,
+		Access: ClusterAccess{
+			Host:     file.Host,
+			Port:     file.Port,
+			Scheme:   file.Scheme,
+			Username: file.Username,
+			Password: file.Password,
+			URL:      file.URL,
+		},
+		CreatedAt: file.CreatedAt,
+	}, nil
+}
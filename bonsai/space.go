@@ -63,8 +63,6 @@ func (o SpaceListOptions) values() url.Values {
 
 // list returns a list of Spaces for the page specified,
 // by performing a GET request against [spaceAPIBasePath].
-//
-// Note: Pagination is not currently supported.
 func (c *SpaceClient) list(ctx context.Context, opt SpaceListOptions) ([]Space, *Response, error) {
 	var (
 		req    *http.Request
@@ -92,7 +90,7 @@ func (c *SpaceClient) list(ctx context.Context, opt SpaceListOptions) ([]Space,
 		return results.Spaces, nil, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Space.List"}), req)
 	if err != nil {
 		return results.Spaces, resp, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -104,36 +102,17 @@ func (c *SpaceClient) list(ctx context.Context, opt SpaceListOptions) ([]Space,
 	return results.Spaces, resp, nil
 }
 
-// All lists all Spaces from the Spaces API.
-func (c *SpaceClient) All(ctx context.Context) ([]Space, error) {
-	var (
-		err  error
-		resp *Response
-	)
-
-	allResults := make([]Space, 0, defaultListResultSize)
-	// No pagination support as yet, but support it for future use
-
-	err = c.all(ctx, newEmptyListOpts(), func(opt listOpts) (*Response, error) {
-		var listResults []Space
-
-		listResults, resp, err = c.list(ctx, SpaceListOptions{listOpts: opt})
-		if err != nil {
-			return resp, fmt.Errorf("client.list failed: %w", err)
-		}
-
-		allResults = append(allResults, listResults...)
-		if len(allResults) >= resp.TotalRecords {
-			resp.MarkPaginationComplete()
-		}
-		return resp, err
+// Iter returns a Pager over the Spaces from the Spaces API, starting from the
+// page specified by page.
+func (c *SpaceClient) Iter(page PageOpts) *Pager[Space] {
+	return newPager(c.Client, page, func(ctx context.Context, o listOpts) ([]Space, *Response, error) {
+		return c.list(ctx, SpaceListOptions{listOpts: o})
 	})
+}
 
-	if err != nil {
-		return allResults, fmt.Errorf("client.all failed: %w", err)
-	}
-
-	return allResults, err //nolint:nolintlint
+// All lists all Spaces from the Spaces API.
+func (c *SpaceClient) All(ctx context.Context) ([]Space, error) {
+	return c.Iter(PageOpts{}).All(ctx)
 }
 
 //nolint:dupl // Allow duplicated code blocks in code paths that may change
@@ -158,7 +137,7 @@ func (c *SpaceClient) GetByPath(ctx context.Context, spacePath string) (Space, e
 		return result, fmt.Errorf("creating new http request for URL (%s): %w", reqURL.String(), err)
 	}
 
-	resp, err = c.Do(ctx, req)
+	resp, err = c.Do(withOperation(ctx, RequestInfo{Operation: "Space.GetByPath"}), req)
 	if err != nil {
 		return result, fmt.Errorf("client.do failed: %w", err)
 	}
@@ -0,0 +1,117 @@
+package bonsai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// VerifyStatus classifies the outcome of Client.Verify.
+type VerifyStatus string
+
+// Verify outcomes.
+const (
+	// VerifyStatusValid indicates that the credentials were accepted.
+	VerifyStatusValid VerifyStatus = "valid"
+	// VerifyStatusUnauthorized indicates that the credentials weren't
+	// recognized (401).
+	VerifyStatusUnauthorized VerifyStatus = "unauthorized"
+	// VerifyStatusForbidden indicates that the credentials were recognized,
+	// but lack access to the account (403).
+	VerifyStatusForbidden VerifyStatus = "forbidden"
+	// VerifyStatusPaymentRequired indicates that the account has a billing
+	// issue (402).
+	VerifyStatusPaymentRequired VerifyStatus = "payment_required"
+	// VerifyStatusUnreachable indicates that no response was received from
+	// the API.
+	VerifyStatusUnreachable VerifyStatus = "unreachable"
+	// VerifyStatusError indicates any other failure, such as a server error
+	// or rate limited request.
+	VerifyStatusError VerifyStatus = "error"
+)
+
+// VerifyResult describes the outcome of Client.Verify.
+type VerifyResult struct {
+	// Status classifies the outcome.
+	Status VerifyStatus
+	// Latency is the time taken for the API to respond, excluding any wait
+	// imposed by the Client's rate limits.
+	Latency time.Duration
+	// RateLimit is the server's view of the Client's rate limit, if it was
+	// reported in the response; see HasRateLimit.
+	RateLimit RateLimitState
+	// HasRateLimit reports whether RateLimit was present in the response.
+	HasRateLimit bool
+	// RequestID is the identifier the API assigned to the request, if any.
+	RequestID string
+}
+
+// OK reports whether the credentials were accepted.
+func (r VerifyResult) OK() bool {
+	return r.Status == VerifyStatusValid
+}
+
+// Verify checks that the Client can reach the API and that its credentials
+// are accepted, by making a single, minimal, authenticated request.
+//
+// The request is subject to the Client's rate limits and middleware, but
+// isn't retried. Unless the Status of the result is VerifyStatusValid, the
+// error describing the failure is returned alongside it:
+//
+//	result, err := client.Verify(ctx)
+//	if err != nil {
+//		log.Fatalf("bonsai credentials %s: %v", result.Status, err)
+//	}
+func (c *Client) Verify(ctx context.Context) (VerifyResult, error) {
+	var result VerifyResult
+
+	req, err := c.NewRequest(ctx, http.MethodGet, ClusterAPIBasePath+"?page=1&size=1", nil)
+	if err != nil {
+		result.Status = VerifyStatusError
+		return result, fmt.Errorf("creating verify request: %w", err)
+	}
+
+	// As doRequest, but timing the request alone.
+	start := time.Now()
+	if err = c.rateLimiter.wait(ctx, req.Method, c.apiPath(req)); err != nil {
+		result.Status = VerifyStatusError
+		return result, fmt.Errorf("failed while awaiting execution per rate-limit: %w", err)
+	}
+	ctx = context.WithValue(ctx, requestInfoKey{}, RequestInfo{
+		Operation:     "Client.Verify",
+		Attempt:       1,
+		RateLimitWait: time.Since(start),
+	})
+
+	start = time.Now()
+	resp, err := c.doer.Do(ctx, req)
+	result.Latency = time.Since(start)
+
+	if resp != nil && resp.httpResponse != nil {
+		result.RateLimit, result.HasRateLimit = resp.RateLimit()
+		result.RequestID = resp.Header.Get(HeaderRequestID)
+	}
+
+	result.Status = verifyStatus(err)
+	return result, err
+}
+
+// verifyStatus classifies the error returned by a verify request.
+func verifyStatus(err error) VerifyStatus {
+	switch {
+	case err == nil:
+		return VerifyStatusValid
+	case errors.Is(err, ErrHTTPStatusUnauthorized):
+		return VerifyStatusUnauthorized
+	case errors.Is(err, ErrHTTPStatusForbidden):
+		return VerifyStatusForbidden
+	case errors.Is(err, ErrHTTPStatusPaymentRequired):
+		return VerifyStatusPaymentRequired
+	case errors.Is(err, ErrNetwork), errors.Is(err, context.DeadlineExceeded):
+		return VerifyStatusUnreachable
+	default:
+		return VerifyStatusError
+	}
+}
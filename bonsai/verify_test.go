@@ -0,0 +1,80 @@
+package bonsai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+func (s *ClientMockTestSuite) TestClient_Verify() {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name   string
+		status int
+		want   bonsai.VerifyStatus
+		err    error
+	}{
+		{"valid", http.StatusOK, bonsai.VerifyStatusValid, nil},
+		{"unauthorized", http.StatusUnauthorized, bonsai.VerifyStatusUnauthorized, bonsai.ErrHTTPStatusUnauthorized},
+		{"forbidden", http.StatusForbidden, bonsai.VerifyStatusForbidden, bonsai.ErrHTTPStatusForbidden},
+		{
+			"payment required", http.StatusPaymentRequired,
+			bonsai.VerifyStatusPaymentRequired, bonsai.ErrHTTPStatusPaymentRequired,
+		},
+		{"server error", http.StatusServiceUnavailable, bonsai.VerifyStatusError, bonsai.ErrHTTPStatusServerError},
+	}
+
+	for i, tc := range testCases {
+		s.Run(tc.name, func() {
+			prefix := fmt.Sprintf("/verify-%d", i)
+			calls := 0
+			s.serveMux.Get(prefix+bonsai.ClusterAPIBasePath, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				s.Equal("1", r.URL.Query().Get("size"))
+
+				w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+				w.Header().Set(bonsai.HeaderRequestID, "req-123")
+				w.Header().Set(bonsai.HeaderRateLimitLimit, "60")
+				w.Header().Set(bonsai.HeaderRateLimitRemaining, "42")
+				w.Header().Set(bonsai.HeaderRateLimitReset, "30")
+				w.WriteHeader(tc.status)
+				if tc.status == http.StatusOK {
+					_, _ = fmt.Fprint(w, `{"clusters": [], "pagination": {"page_number": 1, "page_size": 1, "total_records": 0}}`)
+					return
+				}
+				_, _ = fmt.Fprintf(w, `{"errors": ["%s"], "status": %d}`, http.StatusText(tc.status), tc.status)
+			})
+
+			client := bonsai.NewClient(bonsai.WithEndpoint(s.server.URL + prefix))
+			result, err := client.Verify(ctx)
+			if tc.err == nil {
+				s.NoError(err)
+			} else {
+				s.ErrorIs(err, tc.err)
+			}
+
+			s.Equal(tc.want, result.Status)
+			s.Equal(tc.err == nil, result.OK())
+			s.Equal(1, calls, "verify requests aren't retried")
+			s.Positive(result.Latency)
+			s.Equal("req-123", result.RequestID)
+			s.True(result.HasRateLimit)
+			s.Equal(bonsai.RateLimitState{Limit: 60, Remaining: 42, Reset: 30 * time.Second}, result.RateLimit)
+		})
+	}
+
+	s.Run("unreachable", func() {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		result, err := bonsai.NewClient(bonsai.WithEndpoint(closed.URL)).Verify(ctx)
+		s.ErrorIs(err, bonsai.ErrNetwork)
+		s.Equal(bonsai.VerifyStatusUnreachable, result.Status)
+		s.False(result.HasRateLimit)
+	})
+}
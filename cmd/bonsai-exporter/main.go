@@ -0,0 +1,286 @@
+// Command bonsai-exporter serves the inventory and statistics of the
+// clusters on a Bonsai account as Prometheus metrics.
+//
+// Credentials are read from the BONSAI_API_KEY and BONSAI_API_TOKEN
+// environment variables. Every flag may also be set by an environment
+// variable; see "bonsai-exporter -h".
+//
+// The exporter serves:
+//
+//	/metrics  cluster metrics, in the Prometheus exposition format
+//	/healthz  API reachability and credential validity, as JSON
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/collector"
+)
+
+// Environment variables read by the command.
+const (
+	EnvListenAddress   = "BONSAI_EXPORTER_LISTEN_ADDRESS"
+	EnvRefreshInterval = "BONSAI_EXPORTER_REFRESH_INTERVAL"
+)
+
+// Exporter defaults.
+const (
+	DefaultListenAddress = ":9878"
+	// DefaultRefreshInterval balances freshness against the API's rate
+	// limits; cluster statistics are only updated every 10-15 minutes.
+	DefaultRefreshInterval = collector.DefaultRefreshInterval
+	shutdownTimeout        = 10 * time.Second
+)
+
+// Health statuses reported by /healthz.
+const (
+	healthOK                 = "ok"
+	healthStarting           = "starting"
+	healthInvalidCredentials = "invalid_credentials"
+	healthUnreachable        = "unreachable"
+	healthError              = "error"
+)
+
+// config holds the exporter's configuration.
+type config struct {
+	listenAddress   string
+	refreshInterval time.Duration
+	endpoint        string
+	credentials     bonsai.CredentialPair
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	if err := run(ctx, os.Args[1:], os.Stderr, os.Getenv, logger, nil); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			logger.Error("exiting", slog.Any("error", err))
+		}
+		stop()
+		os.Exit(1)
+	}
+}
+
+// run starts the exporter, and serves until ctx is done. If ready is not nil,
+// the address the exporter listens on is sent to it once serving.
+func run(
+	ctx context.Context,
+	args []string,
+	stderr io.Writer,
+	getenv func(string) string,
+	logger *slog.Logger,
+	ready chan<- net.Addr,
+) error {
+	cfg, err := parseConfig(args, stderr, getenv)
+	if err != nil {
+		return err
+	}
+
+	opts := []bonsai.ClientOption{
+		bonsai.WithApplication(bonsai.Application{Name: "bonsai-exporter", Version: bonsai.Version}),
+		bonsai.WithCredentialPair(cfg.credentials),
+		bonsai.WithMiddleware(bonsai.SlogMiddleware(logger)),
+	}
+	if cfg.endpoint != "" {
+		opts = append(opts, bonsai.WithEndpoint(cfg.endpoint))
+	}
+	client := bonsai.NewClient(opts...)
+
+	// The refresh loop below keeps the collector's clusters fresh, such that
+	// scrapes only list clusters should the loop fall behind.
+	c := collector.New(client, collector.WithRefreshInterval(2*cfg.refreshInterval))
+
+	listener, err := net.Listen("tcp", cfg.listenAddress)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", cfg.listenAddress, err)
+	}
+
+	server := &http.Server{
+		Handler:           newHandler(c),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go refreshLoop(ctx, c, cfg.refreshInterval, logger)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	logger.Info("serving metrics", slog.String("address", listener.Addr().String()))
+	if ready != nil {
+		ready <- listener.Addr()
+	}
+
+	select {
+	case err = <-serveErr:
+		return fmt.Errorf("serving: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+	return nil
+}
+
+// parseConfig reads the exporter's configuration from args, falling back
+// to the environment.
+func parseConfig(args []string, stderr io.Writer, getenv func(string) string) (config, error) {
+	cfg := config{}
+
+	fs := flag.NewFlagSet("bonsai-exporter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.listenAddress, "listen-address", envOr(getenv, EnvListenAddress, DefaultListenAddress),
+		"address to serve /metrics and /healthz on (env "+EnvListenAddress+")")
+	fs.StringVar(&cfg.endpoint, "endpoint", getenv(bonsai.EnvEndpoint),
+		"Bonsai API endpoint (env "+bonsai.EnvEndpoint+")")
+	fs.DurationVar(&cfg.refreshInterval, "refresh-interval", DefaultRefreshInterval,
+		"interval between listing clusters (env "+EnvRefreshInterval+")")
+
+	if v := getenv(EnvRefreshInterval); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid %s: %w", EnvRefreshInterval, err)
+		}
+		cfg.refreshInterval = d
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if fs.NArg() > 0 {
+		return cfg, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if cfg.refreshInterval <= 0 {
+		return cfg, errors.New("refresh interval must be positive")
+	}
+
+	key, token := getenv(bonsai.EnvAccessKey), getenv(bonsai.EnvAccessToken)
+	if key == "" || token == "" {
+		return cfg, fmt.Errorf("%s and %s must be set", bonsai.EnvAccessKey, bonsai.EnvAccessToken)
+	}
+
+	accessKey, err := bonsai.NewAccessKey(key)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid %s: %w", bonsai.EnvAccessKey, err)
+	}
+	accessToken, err := bonsai.NewAccessToken(token)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid %s: %w", bonsai.EnvAccessToken, err)
+	}
+	cfg.credentials = bonsai.CredentialPair{AccessKey: accessKey, AccessToken: accessToken}
+
+	return cfg, nil
+}
+
+func envOr(getenv func(string) string, key, fallback string) string {
+	if v := getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// refreshLoop refreshes the collector's clusters immediately, and then
+// every interval, until ctx is done.
+func refreshLoop(ctx context.Context, c *collector.Collector, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("refreshing clusters", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newHandler returns the exporter's HTTP handler, serving c's metrics.
+func newHandler(c *collector.Collector) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		c,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.Handle("GET /healthz", healthHandler(c))
+	return mux
+}
+
+// health is the body of /healthz responses.
+type health struct {
+	Status      string     `json:"status"`
+	LastRefresh *time.Time `json:"last_refresh,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// healthHandler reports whether the most recent attempt to list clusters
+// succeeded, distinguishing invalid credentials from an unreachable API.
+func healthHandler(c *collector.Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		last, err := c.LastRefresh()
+
+		h := health{Status: healthOK}
+		if !last.IsZero() {
+			h.LastRefresh = &last
+		}
+
+		switch {
+		case err != nil:
+			h.Status, h.Error = classify(err), err.Error()
+		case last.IsZero():
+			h.Status = healthStarting
+		}
+
+		status := http.StatusOK
+		if h.Status != healthOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set(bonsai.HTTPHeaderContentType, bonsai.HTTPContentTypeJSON)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(h)
+	})
+}
+
+// classify maps an error listing clusters to a health status.
+func classify(err error) string {
+	switch {
+	case errors.Is(err, bonsai.ErrHTTPStatusUnauthorized), errors.Is(err, bonsai.ErrHTTPStatusForbidden):
+		return healthInvalidCredentials
+	case errors.Is(err, bonsai.ErrNetwork), errors.Is(err, bonsai.ErrHTTPStatusServerError),
+		errors.Is(err, context.DeadlineExceeded):
+		return healthUnreachable
+	default:
+		return healthError
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/bonsaitest"
+	"github.com/omc/bonsai-api-go/v2/bonsai/collector"
+)
+
+type ExporterTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all exporter tests
+	suite.Suite
+
+	// server is the fake API server, recreated for each test
+	server *bonsaitest.Server
+	// env holds the environment variables visible to the exporter
+	env map[string]string
+}
+
+func (s *ExporterTestSuite) SetupTest() {
+	fixtures := bonsaitest.DefaultFixtures()
+	fixtures.Clusters = []bonsai.Cluster{
+		{
+			Slug:    "search-1234567890",
+			Name:    "search",
+			Plan:    bonsai.Plan{Slug: "standard-sm"},
+			Space:   bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+			Release: bonsai.Release{Slug: "opensearch-2.6.0-mt"},
+			Stats:   bonsai.ClusterStats{Docs: 1200},
+			State:   bonsai.ClusterStateProvisioned,
+		},
+	}
+
+	s.server = bonsaitest.NewServer(bonsaitest.WithFixtures(fixtures))
+	s.env = map[string]string{
+		bonsai.EnvAccessKey:   string(bonsaitest.DefaultAccessKey),
+		bonsai.EnvAccessToken: string(bonsaitest.DefaultAccessToken),
+		bonsai.EnvEndpoint:    s.server.URL,
+		EnvListenAddress:      "127.0.0.1:0",
+	}
+
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func (s *ExporterTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestExporterTestSuite(t *testing.T) {
+	suite.Run(t, new(ExporterTestSuite))
+}
+
+func (s *ExporterTestSuite) getenv(key string) string {
+	return s.env[key]
+}
+
+// get requests p from handler, returning the response status and body.
+func get(handler http.Handler, p string) (int, string) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, p, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func (s *ExporterTestSuite) TestParseConfig() {
+	cfg, err := parseConfig(nil, io.Discard, s.getenv)
+	s.NoError(err)
+	s.Equal("127.0.0.1:0", cfg.listenAddress)
+	s.Equal(DefaultRefreshInterval, cfg.refreshInterval)
+	s.Equal(bonsaitest.DefaultAccessKey, cfg.credentials.AccessKey)
+
+	s.env[EnvRefreshInterval] = "10m"
+	cfg, err = parseConfig(nil, io.Discard, s.getenv)
+	s.NoError(err)
+	s.Equal(10*time.Minute, cfg.refreshInterval, "environment overrides defaults")
+
+	cfg, err = parseConfig([]string{"-refresh-interval", "1m", "-listen-address", ":9999"}, io.Discard, s.getenv)
+	s.NoError(err)
+	s.Equal(time.Minute, cfg.refreshInterval, "flags override the environment")
+	s.Equal(":9999", cfg.listenAddress)
+
+	testCases := []struct {
+		name string
+		env  map[string]string
+		args []string
+	}{
+		{name: "missing credentials", env: map[string]string{bonsai.EnvAccessToken: ""}},
+		{name: "invalid interval", env: map[string]string{EnvRefreshInterval: "often"}},
+		{name: "non-positive interval", args: []string{"-refresh-interval", "0s"}},
+		{name: "unexpected arguments", args: []string{"serve"}},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			getenv := func(key string) string {
+				if v, ok := tc.env[key]; ok {
+					return v
+				}
+				return s.env[key]
+			}
+			_, err := parseConfig(tc.args, io.Discard, getenv)
+			s.Error(err)
+		})
+	}
+}
+
+func (s *ExporterTestSuite) TestHandler() {
+	c := collector.New(s.server.Client())
+	handler := newHandler(c)
+
+	code, body := get(handler, "/healthz")
+	s.Equal(http.StatusServiceUnavailable, code)
+	s.JSONEq(`{"status": "starting"}`, body)
+
+	code, body = get(handler, "/metrics")
+	s.Equal(http.StatusOK, code)
+	s.Contains(body, "bonsai_collector_last_refresh_success 0", "scrapes don't wait for clusters to be listed")
+
+	s.Eventually(func() bool {
+		last, _ := c.LastRefresh()
+		return !last.IsZero()
+	}, time.Second, time.Millisecond, "clusters are listed in the background")
+
+	code, body = get(handler, "/metrics")
+	s.Equal(http.StatusOK, code)
+	s.Contains(body, `bonsai_cluster_docs{plan="standard-sm",release="opensearch-2.6.0-mt",slug="search-1234567890",`)
+	s.Contains(body, `bonsai_clusters{state="PROVISIONED"} 1`)
+	s.Contains(body, "go_goroutines")
+
+	code, body = get(handler, "/healthz")
+	s.Equal(http.StatusOK, code)
+	h := health{}
+	s.NoError(json.Unmarshal([]byte(body), &h))
+	s.Equal(healthOK, h.Status)
+	s.NotNil(h.LastRefresh)
+}
+
+func (s *ExporterTestSuite) TestHealthz() {
+	testCases := []struct {
+		name   string
+		client func() *bonsai.Client
+		expect string
+	}{
+		{
+			name: "invalid credentials",
+			client: func() *bonsai.Client {
+				return s.server.Client(bonsai.WithCredentialPair(bonsai.CredentialPair{
+					AccessKey:   "wrong",
+					AccessToken: "wrong",
+				}))
+			},
+			expect: healthInvalidCredentials,
+		},
+		{
+			name: "unreachable",
+			client: func() *bonsai.Client {
+				closed := httptest.NewServer(http.NotFoundHandler())
+				closed.Close()
+				return s.server.Client(bonsai.WithEndpoint(closed.URL))
+			},
+			expect: healthUnreachable,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			c := collector.New(tc.client())
+			s.Error(c.Refresh(context.Background()))
+
+			code, body := get(newHandler(c), "/healthz")
+			s.Equal(http.StatusServiceUnavailable, code)
+
+			h := health{}
+			s.NoError(json.Unmarshal([]byte(body), &h))
+			s.Equal(tc.expect, h.Status)
+			s.NotEmpty(h.Error)
+		})
+	}
+}
+
+func (s *ExporterTestSuite) TestRun() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ready := make(chan net.Addr, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, nil, io.Discard, s.getenv, logger, ready)
+	}()
+
+	var addr net.Addr
+	select {
+	case addr = <-ready:
+	case err := <-done:
+		s.FailNow("exporter exited early", "error: %v", err)
+	}
+
+	s.Eventually(func() bool {
+		resp, err := http.Get("http://" + addr.String() + "/healthz") //nolint:noctx // test request
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond, "clusters are refreshed in the background")
+
+	resp, err := http.Get("http://" + addr.String() + "/metrics") //nolint:noctx // test request
+	s.NoError(err)
+	body, err := io.ReadAll(resp.Body)
+	s.NoError(err)
+	s.NoError(resp.Body.Close())
+	s.True(strings.Contains(string(body), "bonsai_cluster_docs"))
+
+	cancel()
+	s.NoError(<-done)
+}
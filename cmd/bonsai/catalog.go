@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+func plansTable(plans ...bonsai.Plan) table {
+	t := table{headers: []string{"SLUG", "NAME", "PRICE_IN_CENTS", "SINGLE_TENANT", "PRIVATE_NETWORK"}}
+	for _, p := range plans {
+		t.rows = append(t.rows, []string{
+			p.Slug,
+			p.Name,
+			strconv.FormatInt(p.PriceInCents, 10),
+			formatBool(p.SingleTenant),
+			formatBool(p.PrivateNetwork),
+		})
+	}
+	return t
+}
+
+func spacesTable(spaces ...bonsai.Space) table {
+	t := table{headers: []string{"PATH", "PROVIDER", "REGION", "PRIVATE_NETWORK"}}
+	for _, s := range spaces {
+		provider, region := "-", s.Region
+		if s.Cloud != nil {
+			provider, region = s.Cloud.Provider, s.Cloud.Region
+		}
+		t.rows = append(t.rows, []string{s.Path, provider, region, formatBool(s.PrivateNetwork)})
+	}
+	return t
+}
+
+func releasesTable(releases ...bonsai.Release) table {
+	t := table{headers: []string{"SLUG", "NAME", "SERVICE_TYPE", "VERSION", "MULTITENANT"}}
+	for _, r := range releases {
+		t.rows = append(t.rows, []string{r.Slug, r.Name, r.ServiceType, r.Version, formatBool(r.MultiTenant)})
+	}
+	return t
+}
+
+func (a *app) planCommands() map[string]func(context.Context, []string) error {
+	return map[string]func(context.Context, []string) error{
+		"list": func(ctx context.Context, args []string) error {
+			if _, err := exactArgs(a.newFlagSet("plans list"), args); err != nil {
+				return err
+			}
+			plans, err := a.client.Plan.All(ctx)
+			if err != nil {
+				return fmt.Errorf("listing plans: %w", err)
+			}
+			return a.printer.print(plans, plansTable(plans...))
+		},
+		"get": func(ctx context.Context, args []string) error {
+			positional, err := exactArgs(a.newFlagSet("plans get"), args, "<slug>")
+			if err != nil {
+				return err
+			}
+			plan, err := a.client.Plan.GetBySlug(ctx, positional[0])
+			if err != nil {
+				return fmt.Errorf("getting plan: %w", err)
+			}
+			return a.printer.print(plan, plansTable(plan))
+		},
+	}
+}
+
+func (a *app) spaceCommands() map[string]func(context.Context, []string) error {
+	return map[string]func(context.Context, []string) error{
+		"list": func(ctx context.Context, args []string) error {
+			if _, err := exactArgs(a.newFlagSet("spaces list"), args); err != nil {
+				return err
+			}
+			spaces, err := a.client.Space.All(ctx)
+			if err != nil {
+				return fmt.Errorf("listing spaces: %w", err)
+			}
+			return a.printer.print(spaces, spacesTable(spaces...))
+		},
+		"get": func(ctx context.Context, args []string) error {
+			positional, err := exactArgs(a.newFlagSet("spaces get"), args, "<path>")
+			if err != nil {
+				return err
+			}
+			space, err := a.client.Space.GetByPath(ctx, positional[0])
+			if err != nil {
+				return fmt.Errorf("getting space: %w", err)
+			}
+			return a.printer.print(space, spacesTable(space))
+		},
+	}
+}
+
+func (a *app) releaseCommands() map[string]func(context.Context, []string) error {
+	return map[string]func(context.Context, []string) error{
+		"list": func(ctx context.Context, args []string) error {
+			if _, err := exactArgs(a.newFlagSet("releases list"), args); err != nil {
+				return err
+			}
+			releases, err := a.client.Release.All(ctx)
+			if err != nil {
+				return fmt.Errorf("listing releases: %w", err)
+			}
+			return a.printer.print(releases, releasesTable(releases...))
+		},
+		"get": func(ctx context.Context, args []string) error {
+			positional, err := exactArgs(a.newFlagSet("releases get"), args, "<slug>")
+			if err != nil {
+				return err
+			}
+			release, err := a.client.Release.GetBySlug(ctx, positional[0])
+			if err != nil {
+				return fmt.Errorf("getting release: %w", err)
+			}
+			return a.printer.print(release, releasesTable(release))
+		},
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+func (a *app) clusterCommands() map[string]func(context.Context, []string) error {
+	return map[string]func(context.Context, []string) error{
+		"list":    a.clustersList,
+		"get":     a.clustersGet,
+		"create":  a.clustersCreate,
+		"update":  a.clustersUpdate,
+		"destroy": a.clustersDestroy,
+		"wait":    a.clustersWait,
+	}
+}
+
+func clustersTable(clusters ...bonsai.Cluster) table {
+	t := table{headers: []string{"SLUG", "NAME", "PLAN", "SPACE", "RELEASE", "STATE"}}
+	for _, c := range clusters {
+		t.rows = append(t.rows, []string{c.Slug, c.Name, c.Plan.Slug, c.Space.Path, c.Release.Slug, string(c.State)})
+	}
+	return t
+}
+
+func (a *app) clustersList(ctx context.Context, args []string) error {
+	var (
+		opt  bonsai.ClusterAllOpts
+		page bonsai.PageOpts
+		fs   = a.newFlagSet("clusters list")
+	)
+	fs.StringVar(&opt.Query, "q", "", "filter clusters by name")
+	fs.StringVar(&opt.Tenancy, "tenancy", "", `filter clusters by tenancy: "parent" or "child"`)
+	fs.StringVar(&opt.Location, "location", "", "filter clusters by space path prefix")
+	fs.IntVar(&page.Page, "page", 0, "list only this page of results")
+	fs.IntVar(&page.Size, "size", 0, "page size")
+
+	if _, err := exactArgs(fs, args); err != nil {
+		return err
+	}
+
+	var (
+		clusters []bonsai.Cluster
+		err      error
+	)
+	if page.Page > 0 {
+		clusters, _, err = a.client.Cluster.List(ctx, opt, page)
+	} else {
+		clusters, err = a.client.Cluster.Iter(opt, page).All(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("listing clusters: %w", err)
+	}
+
+	return a.printer.print(clusters, clustersTable(clusters...))
+}
+
+func (a *app) clustersGet(ctx context.Context, args []string) error {
+	positional, err := exactArgs(a.newFlagSet("clusters get"), args, "<slug>")
+	if err != nil {
+		return err
+	}
+
+	cluster, err := a.client.Cluster.GetBySlug(ctx, positional[0])
+	if err != nil {
+		return fmt.Errorf("getting cluster: %w", err)
+	}
+
+	return a.printer.print(cluster, clustersTable(cluster))
+}
+
+func (a *app) clustersCreate(ctx context.Context, args []string) error {
+	var (
+		opt  bonsai.ClusterCreateOpts
+		wait waitFlags
+		fs   = a.newFlagSet("clusters create")
+	)
+	fs.StringVar(&opt.Name, "name", "", "name of the new cluster (required)")
+	fs.StringVar(&opt.Plan, "plan", "", "slug of the plan for the new cluster")
+	fs.StringVar(&opt.Space, "space", "", "path of the space to deploy the new cluster to")
+	fs.StringVar(&opt.Release, "release", "", "slug of the search release for the new cluster")
+	wait.register(fs, true)
+
+	if _, err := exactArgs(fs, args); err != nil {
+		return err
+	}
+	if opt.Name == "" {
+		return usagef("clusters create requires -name")
+	}
+
+	result, err := a.client.Cluster.Create(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("creating cluster: %w", err)
+	}
+
+	// The credentials of a new cluster are only shown once, so they're
+	// revealed rather than redacted.
+	revealed := struct {
+		Message string                       `json:"message"`
+		Monitor string                       `json:"monitor"`
+		Access  bonsai.RevealedClusterAccess `json:"access"`
+	}{result.Message, result.Monitor, result.Access.Reveal()}
+
+	if err = a.printer.print(revealed, fields(
+		"message", result.Message,
+		"monitor", result.Monitor,
+		"host", result.Access.Host,
+		"port", strconv.Itoa(result.Access.Port),
+		"scheme", result.Access.Scheme,
+		"user", result.Access.Username,
+		"pass", result.Access.Password,
+		"url", result.Access.URL,
+	)); err != nil {
+		return err
+	}
+
+	return a.waitAfter(ctx, wait, bonsai.ClusterSlugFromURI(result.Monitor), bonsai.ClusterStateProvisioned)
+}
+
+func (a *app) clustersUpdate(ctx context.Context, args []string) error {
+	var (
+		opt  bonsai.ClusterUpdateOpts
+		wait waitFlags
+		fs   = a.newFlagSet("clusters update")
+	)
+	fs.StringVar(&opt.Name, "name", "", "new name of the cluster (required)")
+	fs.StringVar(&opt.Plan, "plan", "", "slug of the new plan for the cluster")
+	wait.register(fs, true)
+
+	positional, err := exactArgs(fs, args, "<slug>")
+	if err != nil {
+		return err
+	}
+	if opt.Name == "" {
+		return usagef("clusters update requires -name")
+	}
+
+	result, err := a.client.Cluster.Update(ctx, positional[0], opt)
+	if err != nil {
+		return fmt.Errorf("updating cluster: %w", err)
+	}
+
+	if err = a.printer.print(result, fields("message", result.Message, "monitor", result.Monitor)); err != nil {
+		return err
+	}
+
+	return a.waitAfter(ctx, wait, positional[0], bonsai.ClusterStateProvisioned)
+}
+
+func (a *app) clustersDestroy(ctx context.Context, args []string) error {
+	var (
+		wait waitFlags
+		fs   = a.newFlagSet("clusters destroy")
+	)
+	wait.register(fs, true)
+
+	positional, err := exactArgs(fs, args, "<slug>")
+	if err != nil {
+		return err
+	}
+
+	result, err := a.client.Cluster.Destroy(ctx, positional[0])
+	if err != nil {
+		return fmt.Errorf("destroying cluster: %w", err)
+	}
+
+	if err = a.printer.print(result, fields("message", result.Message, "monitor", result.Monitor)); err != nil {
+		return err
+	}
+
+	return a.waitAfter(ctx, wait, positional[0], bonsai.ClusterStateDeprovisioned)
+}
+
+func (a *app) clustersWait(ctx context.Context, args []string) error {
+	var (
+		wait   waitFlags
+		states string
+		fs     = a.newFlagSet("clusters wait")
+	)
+	fs.StringVar(&states, "state", string(bonsai.ClusterStateProvisioned), "comma-separated list of states to wait for")
+	wait.register(fs, false)
+
+	positional, err := exactArgs(fs, args, "<slug>")
+	if err != nil {
+		return err
+	}
+
+	var targets []bonsai.ClusterState
+	for _, state := range strings.Split(states, ",") {
+		targets = append(targets, bonsai.ClusterState(strings.ToUpper(strings.TrimSpace(state))))
+	}
+
+	cluster, err := a.client.Cluster.WaitForState(ctx, positional[0], wait.opts(targets...))
+	if err != nil {
+		return fmt.Errorf("waiting for cluster: %w", err)
+	}
+
+	return a.printer.print(cluster, clustersTable(cluster))
+}
+
+// waitFlags holds the flags shared by commands which wait for a cluster.
+type waitFlags struct {
+	enabled  bool
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func (w *waitFlags) register(fs *flag.FlagSet, optional bool) {
+	if optional {
+		fs.BoolVar(&w.enabled, "wait", false, "wait for the cluster to settle")
+	} else {
+		w.enabled = true
+	}
+	fs.DurationVar(&w.interval, "interval", bonsai.DefaultClusterWaitPollInterval, "initial interval between state checks")
+	fs.DurationVar(&w.timeout, "timeout", 30*time.Minute, "maximum time to wait")
+}
+
+func (w *waitFlags) opts(targets ...bonsai.ClusterState) bonsai.ClusterWaitOpts {
+	return bonsai.ClusterWaitOpts{
+		Targets:      targets,
+		PollInterval: w.interval,
+		Timeout:      w.timeout,
+	}
+}
+
+// waitAfter waits for the cluster to reach target, if waiting was requested.
+func (a *app) waitAfter(ctx context.Context, w waitFlags, slug string, target bonsai.ClusterState) error {
+	if !w.enabled {
+		return nil
+	}
+	if slug == "" {
+		return errors.New("unable to determine the cluster slug to wait for")
+	}
+
+	fmt.Fprintf(a.stderr, "Waiting for cluster %s to reach %s...\n", slug, target)
+
+	cluster, err := a.client.Cluster.WaitForState(ctx, slug, w.opts(target))
+	if err != nil {
+		return fmt.Errorf("waiting for cluster: %w", err)
+	}
+
+	fmt.Fprintf(a.stderr, "Cluster %s is %s.\n", cluster.Slug, cluster.State)
+	return nil
+}
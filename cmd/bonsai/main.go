@@ -0,0 +1,283 @@
+// Command bonsai is a command-line client for the Bonsai API.
+//
+// Credentials are read from the BONSAI_API_KEY and BONSAI_API_TOKEN
+// environment variables.
+//
+// Usage:
+//
+//	bonsai [flags] <resource> <action> [arguments]
+//
+// Run "bonsai -h" for the full list of resources, actions and exit codes.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+)
+
+// Exit codes, each identifying a class of failure.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitUnauthorized
+	exitPaymentRequired
+	exitForbidden
+	exitNotFound
+	exitUnprocessableEntity
+	exitTooManyRequests
+	exitUnexpectedState
+	exitBadRequest
+	exitConflict
+	exitServerError
+	exitNetwork
+)
+
+const usageText = `Usage: bonsai [flags] <resource> <action> [arguments]
+
+Resources and actions:
+  clusters list [-q query] [-tenancy parent|child] [-location path] [-page n] [-size n]
+  clusters get <slug>
+  clusters create -name name [-plan slug] [-space path] [-release slug] [-wait]
+  clusters update <slug> -name name [-plan slug] [-wait]
+  clusters destroy <slug> [-wait]
+  clusters wait <slug> [-state state[,state...]] [-timeout duration]
+  plans list | plans get <slug>
+  spaces list | spaces get <path>
+  releases list | releases get <slug>
+
+Credentials are read from the BONSAI_API_KEY and BONSAI_API_TOKEN
+environment variables. BONSAI_API_ENDPOINT optionally overrides the
+API endpoint.
+
+Exit codes:
+  0  success
+  1  other error
+  2  invalid usage or options
+  3  unauthorized (401)
+  4  payment required (402)
+  5  forbidden (403)
+  6  not found (404)
+  7  unprocessable entity (422)
+  8  too many requests (429)
+  9  cluster reached an unexpected state while waiting
+  10 bad request (400)
+  11 conflict (409)
+  12 server error (5xx)
+  13 network error; no response was received
+
+Flags:
+`
+
+// usageError reports invalid command-line usage.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, a ...any) error {
+	return usageError{msg: fmt.Sprintf(format, a...)}
+}
+
+// app holds the state shared by all commands.
+type app struct {
+	client  *bonsai.Client
+	printer printer
+	stderr  io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command line args, and returns the process exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("bonsai", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usageText)
+		fs.PrintDefaults()
+	}
+
+	var (
+		output   = fs.String("o", formatTable, "output format: table, json or yaml")
+		endpoint = fs.String("endpoint", getenv(bonsai.EnvEndpoint), "Bonsai API endpoint")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	err := execute(ctx, fs.Args(), stdout, stderr, getenv, *output, *endpoint)
+	if err != nil {
+		fmt.Fprintf(stderr, "bonsai: %v\n", err)
+		if errors.As(err, &usageError{}) {
+			fmt.Fprintln(stderr, "Run 'bonsai -h' for usage.")
+		}
+	}
+	return exitCode(err)
+}
+
+func execute(
+	ctx context.Context,
+	args []string,
+	stdout, stderr io.Writer,
+	getenv func(string) string,
+	output, endpoint string,
+) error {
+	p, err := newPrinter(output, stdout)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		return usagef("expected a resource and an action")
+	}
+
+	client, err := newClient(getenv, endpoint)
+	if err != nil {
+		return err
+	}
+
+	a := &app{client: client, printer: p, stderr: stderr}
+	resource, action, rest := args[0], args[1], args[2:]
+
+	var commands map[string]func(context.Context, []string) error
+	switch resource {
+	case "clusters", "cluster":
+		commands = a.clusterCommands()
+	case "plans", "plan":
+		commands = a.planCommands()
+	case "spaces", "space":
+		commands = a.spaceCommands()
+	case "releases", "release":
+		commands = a.releaseCommands()
+	default:
+		return usagef("unknown resource %q", resource)
+	}
+
+	cmd, ok := commands[action]
+	if !ok {
+		return usagef("unknown action %q for %s", action, resource)
+	}
+	return cmd(ctx, rest)
+}
+
+// newClient creates a Client from the credentials held in the environment.
+func newClient(getenv func(string) string, endpoint string) (*bonsai.Client, error) {
+	key, token := getenv(bonsai.EnvAccessKey), getenv(bonsai.EnvAccessToken)
+	if key == "" || token == "" {
+		return nil, usagef("%s and %s must be set", bonsai.EnvAccessKey, bonsai.EnvAccessToken)
+	}
+
+	accessKey, err := bonsai.NewAccessKey(key)
+	if err != nil {
+		return nil, usagef("invalid %s: %v", bonsai.EnvAccessKey, err)
+	}
+	accessToken, err := bonsai.NewAccessToken(token)
+	if err != nil {
+		return nil, usagef("invalid %s: %v", bonsai.EnvAccessToken, err)
+	}
+
+	opts := []bonsai.ClientOption{
+		bonsai.WithApplication(bonsai.Application{Name: "bonsai-cli", Version: bonsai.Version}),
+		bonsai.WithCredentialPair(bonsai.CredentialPair{AccessKey: accessKey, AccessToken: accessToken}),
+		bonsai.WithCreateValidation(true),
+	}
+	if endpoint != "" {
+		opts = append(opts, bonsai.WithEndpoint(endpoint))
+	}
+	return bonsai.NewClient(opts...), nil
+}
+
+// exitCode maps err to the process exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usageError{}), errors.Is(err, bonsai.ErrInvalidOption):
+		return exitUsage
+	case errors.Is(err, bonsai.ErrHTTPStatusUnauthorized):
+		return exitUnauthorized
+	case errors.Is(err, bonsai.ErrHTTPStatusPaymentRequired):
+		return exitPaymentRequired
+	case errors.Is(err, bonsai.ErrHTTPStatusForbidden):
+		return exitForbidden
+	case errors.Is(err, bonsai.ErrHTTPStatusNotFound):
+		return exitNotFound
+	case errors.Is(err, bonsai.ErrHTTPStatusUnprocessableEntity):
+		return exitUnprocessableEntity
+	case errors.Is(err, bonsai.ErrHTTPStatusTooManyRequests):
+		return exitTooManyRequests
+	case errors.Is(err, bonsai.ErrClusterUnexpectedState):
+		return exitUnexpectedState
+	case errors.Is(err, bonsai.ErrHTTPStatusBadRequest):
+		return exitBadRequest
+	case errors.Is(err, bonsai.ErrHTTPStatusConflict):
+		return exitConflict
+	case errors.Is(err, bonsai.ErrHTTPStatusServerError):
+		return exitServerError
+	case errors.Is(err, bonsai.ErrNetwork):
+		return exitNetwork
+	default:
+		return exitError
+	}
+}
+
+// parseFlags parses args with fs, allowing flags and positional arguments to
+// be interleaved, and returns the positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError{msg: err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet returns a FlagSet for the named command, which reports
+// errors rather than exiting.
+func (a *app) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	return fs
+}
+
+// exactArgs parses args with fs, and requires exactly n positional arguments,
+// described by names.
+func exactArgs(fs *flag.FlagSet, args []string, names ...string) ([]string, error) {
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(positional) != len(names) {
+		if len(names) == 0 {
+			return nil, usagef("%s takes no arguments", fs.Name())
+		}
+		return nil, usagef("%s requires: %s", fs.Name(), strings.Join(names, " "))
+	}
+	return positional, nil
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+
+	"github.com/omc/bonsai-api-go/v2/bonsai"
+	"github.com/omc/bonsai-api-go/v2/bonsai/bonsaitest"
+)
+
+type CommandTestSuite struct {
+	// Assertions embedded here allows all tests to reach through the suite to access assertion methods
+	*require.Assertions
+	// Suite is the testify/suite used for all command tests
+	suite.Suite
+
+	// server is the fake API server, recreated for each test
+	server *bonsaitest.Server
+	// env holds the environment variables visible to the command
+	env map[string]string
+}
+
+func (s *CommandTestSuite) SetupTest() {
+	s.server = bonsaitest.NewServer(
+		bonsaitest.WithTransitionReads(0),
+		bonsaitest.WithFixtures(bonsaitest.Fixtures{
+			Clusters: []bonsai.Cluster{
+				{
+					Slug:    "existing-cluster-1234567890",
+					Name:    "existing_cluster",
+					Plan:    bonsai.Plan{Slug: "standard-sm"},
+					Space:   bonsai.Space{Path: "omc/bonsai/us-east-1/common"},
+					Release: bonsai.Release{Slug: "opensearch-2.6.0-mt"},
+					State:   bonsai.ClusterStateProvisioned,
+				},
+			},
+			Plans:    bonsaitest.DefaultFixtures().Plans,
+			Spaces:   bonsaitest.DefaultFixtures().Spaces,
+			Releases: bonsaitest.DefaultFixtures().Releases,
+		}),
+	)
+	s.env = map[string]string{
+		bonsai.EnvAccessKey:   string(bonsaitest.DefaultAccessKey),
+		bonsai.EnvAccessToken: string(bonsaitest.DefaultAccessToken),
+		bonsai.EnvEndpoint:    s.server.URL,
+	}
+
+	// configure testify
+	s.Assertions = require.New(s.T())
+}
+
+func (s *CommandTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestCommandTestSuite(t *testing.T) {
+	suite.Run(t, new(CommandTestSuite))
+}
+
+// run executes the command with args, returning its exit code and output.
+func (s *CommandTestSuite) run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr, func(key string) string {
+		return s.env[key]
+	})
+	return code, stdout.String(), stderr.String()
+}
+
+func (s *CommandTestSuite) TestClustersListTable() {
+	code, stdout, stderr := s.run("clusters", "list")
+	s.Equal(exitOK, code, stderr)
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	s.Len(lines, 2)
+	s.Equal([]string{"SLUG", "NAME", "PLAN", "SPACE", "RELEASE", "STATE"}, strings.Fields(lines[0]))
+	s.Equal(
+		[]string{
+			"existing-cluster-1234567890",
+			"existing_cluster",
+			"standard-sm",
+			"omc/bonsai/us-east-1/common",
+			"opensearch-2.6.0-mt",
+			"PROVISIONED",
+		},
+		strings.Fields(lines[1]),
+	)
+}
+
+func (s *CommandTestSuite) TestClustersGetJSON() {
+	code, stdout, stderr := s.run("-o", "json", "clusters", "get", "existing-cluster-1234567890")
+	s.Equal(exitOK, code, stderr)
+
+	var cluster bonsai.Cluster
+	s.NoError(json.Unmarshal([]byte(stdout), &cluster))
+	s.Equal("existing-cluster-1234567890", cluster.Slug)
+	s.Equal(bonsai.ClusterStateProvisioned, cluster.State)
+}
+
+func (s *CommandTestSuite) TestPlansListYAML() {
+	code, stdout, stderr := s.run("-o", "yaml", "plans", "list")
+	s.Equal(exitOK, code, stderr)
+
+	var plans []map[string]any
+	s.NoError(yaml.Unmarshal([]byte(stdout), &plans))
+	s.Len(plans, len(bonsaitest.DefaultFixtures().Plans))
+	s.Equal("sandbox-aws-us-east-1", plans[0]["slug"])
+}
+
+func (s *CommandTestSuite) TestCatalogGet() {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"plans", "get", "standard-sm"}, expected: "standard-sm"},
+		{args: []string{"spaces", "get", "omc/bonsai/us-east-1/common"}, expected: "omc/bonsai/us-east-1/common"},
+		{args: []string{"releases", "get", "opensearch-2.6.0"}, expected: "opensearch-2.6.0"},
+	}
+
+	for _, tc := range testCases {
+		s.Run(strings.Join(tc.args, " "), func() {
+			code, stdout, stderr := s.run(tc.args...)
+			s.Equal(exitOK, code, stderr)
+			s.Contains(stdout, tc.expected)
+		})
+	}
+}
+
+func (s *CommandTestSuite) TestClustersCreateAndWait() {
+	code, stdout, stderr := s.run(
+		"-o", "json",
+		"clusters", "create",
+		"-name", "new_cluster",
+		"-plan", "sandbox-aws-us-east-1",
+		"-space", "omc/bonsai/us-east-1/common",
+		"-release", "opensearch-2.6.0-mt",
+		"-wait", "-interval", "1ms",
+	)
+	s.Equal(exitOK, code, stderr)
+	s.Contains(stderr, "PROVISIONED")
+
+	var result bonsai.ClustersResultCreate
+	s.NoError(json.Unmarshal([]byte(stdout), &result))
+	s.NotEmpty(result.Access.Password, "credentials of the new cluster are shown")
+	s.NotEqual("REDACTED", result.Access.Password, "credentials of the new cluster are shown")
+
+	cluster, ok := s.server.Cluster(bonsai.ClusterSlugFromURI(result.Monitor))
+	s.True(ok)
+	s.Equal("new_cluster", cluster.Name)
+	s.Equal(bonsai.ClusterStateProvisioned, cluster.State)
+}
+
+func (s *CommandTestSuite) TestClustersUpdate() {
+	code, _, stderr := s.run("clusters", "update", "existing-cluster-1234567890", "-name", "renamed", "-plan", "business-sm")
+	s.Equal(exitOK, code, stderr)
+
+	cluster, ok := s.server.Cluster("existing-cluster-1234567890")
+	s.True(ok)
+	s.Equal("renamed", cluster.Name)
+}
+
+func (s *CommandTestSuite) TestClustersDestroyAndWait() {
+	code, _, stderr := s.run("clusters", "destroy", "existing-cluster-1234567890", "-wait", "-interval", "1ms")
+	s.Equal(exitOK, code, stderr)
+
+	cluster, ok := s.server.Cluster("existing-cluster-1234567890")
+	s.True(ok)
+	s.Equal(bonsai.ClusterStateDeprovisioned, cluster.State)
+}
+
+func (s *CommandTestSuite) TestClustersWaitUnexpectedState() {
+	s.server.SetClusterState("existing-cluster-1234567890", bonsai.ClusterStateDisabled)
+
+	code, _, stderr := s.run("clusters", "wait", "existing-cluster-1234567890", "-interval", "1ms")
+	s.Equal(exitUnexpectedState, code, stderr)
+}
+
+func (s *CommandTestSuite) TestExitCodes() {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		fail     string
+		status   int
+		expected int
+	}{
+		{
+			name:     "missing credentials",
+			env:      map[string]string{bonsai.EnvAccessKey: ""},
+			args:     []string{"clusters", "list"},
+			expected: exitUsage,
+		},
+		{
+			name:     "unknown resource",
+			args:     []string{"widgets", "list"},
+			expected: exitUsage,
+		},
+		{
+			name:     "unknown output format",
+			args:     []string{"-o", "xml", "clusters", "list"},
+			expected: exitUsage,
+		},
+		{
+			name:     "missing argument",
+			args:     []string{"clusters", "get"},
+			expected: exitUsage,
+		},
+		{
+			name:     "invalid tenancy",
+			args:     []string{"clusters", "list", "-tenancy", "sibling"},
+			expected: exitUsage,
+		},
+		{
+			name:     "unauthorized",
+			env:      map[string]string{bonsai.EnvAccessToken: "wrong"},
+			args:     []string{"clusters", "list"},
+			expected: exitUnauthorized,
+		},
+		{
+			name:     "not found",
+			args:     []string{"clusters", "get", "missing-cluster"},
+			expected: exitNotFound,
+		},
+		{
+			name:     "payment required",
+			args:     []string{"plans", "list"},
+			fail:     http.MethodGet + " " + bonsai.PlanAPIBasePath,
+			status:   http.StatusPaymentRequired,
+			expected: exitPaymentRequired,
+		},
+		{
+			name:     "forbidden",
+			args:     []string{"plans", "list"},
+			fail:     http.MethodGet + " " + bonsai.PlanAPIBasePath,
+			status:   http.StatusForbidden,
+			expected: exitForbidden,
+		},
+		{
+			name:     "conflict",
+			args:     []string{"clusters", "update", "existing-cluster-1234567890", "-name", "renamed"},
+			fail:     http.MethodPut + " " + bonsai.ClusterAPIBasePath + "/existing-cluster-1234567890",
+			status:   http.StatusConflict,
+			expected: exitConflict,
+		},
+		{
+			name:     "server error",
+			args:     []string{"plans", "list"},
+			fail:     http.MethodGet + " " + bonsai.PlanAPIBasePath,
+			status:   http.StatusInternalServerError,
+			expected: exitServerError,
+		},
+		{
+			name:     "network error",
+			env:      map[string]string{bonsai.EnvEndpoint: "http://127.0.0.1:1"},
+			args:     []string{"plans", "list"},
+			expected: exitNetwork,
+		},
+		{
+			name:     "plan not in catalog",
+			args:     []string{"clusters", "create", "-name", "new_cluster", "-plan", "missing-plan"},
+			expected: exitUsage,
+		},
+		{
+			name:     "unprocessable entity",
+			args:     []string{"clusters", "create", "-name", "new_cluster"},
+			fail:     http.MethodPost + " " + bonsai.ClusterAPIBasePath,
+			status:   http.StatusUnprocessableEntity,
+			expected: exitUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			defer s.TearDownTest()
+
+			for k, v := range tc.env {
+				s.env[k] = v
+			}
+			if tc.fail != "" {
+				method, path, _ := strings.Cut(tc.fail, " ")
+				s.server.FailNext(method, path, tc.status)
+			}
+
+			code, _, stderr := s.run(tc.args...)
+			s.Equal(tc.expected, code, stderr)
+			s.Contains(stderr, "bonsai: ")
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// table is the tabular representation of a result.
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// printer writes results in the configured format.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func newPrinter(format string, w io.Writer) (printer, error) {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return printer{format: format, w: w}, nil
+	default:
+		return printer{}, usagef("unknown output format %q", format)
+	}
+}
+
+// print writes v, or its tabular representation t when printing tables.
+func (p printer) print(v any, t table) error {
+	switch p.format {
+	case formatJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		return p.printYAML(v)
+	default:
+		return p.printTable(t)
+	}
+}
+
+// printYAML writes v as YAML, with the same field names as its JSON
+// representation.
+func (p printer) printYAML(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling result: %w", err)
+	}
+
+	var generic any
+	if err = json.Unmarshal(data, &generic); err != nil {
+		return fmt.Errorf("unmarshaling result: %w", err)
+	}
+
+	enc := yaml.NewEncoder(p.w)
+	enc.SetIndent(2)
+	if err = enc.Encode(generic); err != nil {
+		return fmt.Errorf("encoding result as yaml: %w", err)
+	}
+	return enc.Close()
+}
+
+func (p printer) printTable(t table) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	if len(t.headers) > 0 {
+		fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	}
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// fields returns a two-column table of name/value pairs.
+func fields(pairs ...string) table {
+	t := table{headers: []string{"FIELD", "VALUE"}}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		t.rows = append(t.rows, []string{pairs[i], pairs[i+1]})
+	}
+	return t
+}
+
+// formatBool formats an optional boolean for display.
+func formatBool(b *bool) string {
+	if b == nil {
+		return "-"
+	}
+	return strconv.FormatBool(*b)
+}
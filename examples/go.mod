@@ -6,6 +6,7 @@ require github.com/omc/bonsai-api-go/v2 v2.4.0
 
 require (
 	github.com/google/go-querystring v1.1.0 // indirect
+	golang.org/x/crypto v0.22.0 // indirect
 	golang.org/x/net v0.24.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 	golang.org/x/time v0.5.0 // indirect